@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"lotto-notifications/internal/api"
+	"lotto-notifications/internal/config"
+	"lotto-notifications/internal/database"
+	"lotto-notifications/internal/logging"
+	"lotto-notifications/internal/repository"
+)
+
+func main() {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logging.Init(cfg.Environment)
+
+	err = database.Initialize(cfg.DBPath)
+	if err != nil {
+		slog.Error("Failed to initialize database", "error", err)
+		return
+	}
+	defer database.Close()
+
+	db, err := database.GetDB()
+	if err != nil {
+		slog.Error("Failed to get database", "error", err)
+		return
+	}
+
+	repo := repository.NewRepository(db)
+	server := &http.Server{
+		Addr:              cfg.APIAddr,
+		Handler:           api.NewHandler(repo),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		slog.Info("Listening", "addr", cfg.APIAddr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Failed to serve", "error", err)
+			stop()
+		}
+	}()
+
+	<-ctx.Done()
+	slog.Info("Shutting down gracefully...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Failed to shut down server", "error", err)
+	}
+	slog.Info("Shutdown complete")
+}
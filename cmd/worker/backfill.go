@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"lotto-notifications/internal/config"
+	"lotto-notifications/internal/database"
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/repository"
+	"lotto-notifications/internal/rules"
+	"lotto-notifications/internal/service"
+)
+
+const backfillUsage = "usage: worker backfill GAME <FROM [TO]|draw ID>, dates as YYYY-MM-DD"
+
+// runBackfill handles `worker backfill GAME ...`, an interrupted backfill resumes when run again with the same FROM
+func runBackfill(cfg *config.Config, args []string) error {
+	if len(args) < 2 || len(args) > 3 {
+		return errors.New(backfillUsage)
+	}
+	gameRules, ok := rules.Get(models.GameType(args[0]))
+	if !ok {
+		return fmt.Errorf("unknown game %q", args[0])
+	}
+	gameType := gameRules.GameType
+
+	if err := database.Initialize(cfg.DBPath); err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer database.Close()
+
+	db, err := database.GetDB()
+	if err != nil {
+		return err
+	}
+	lottoClient, breaker, err := newLottoClient(cfg)
+	if err != nil {
+		return err
+	}
+	// no outbox channels, past draws are not notified
+	service := service.NewService(lottoClient, repository.NewRepository(db), nil)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if args[1] == "draw" {
+		if len(args) != 3 {
+			return errors.New(backfillUsage)
+		}
+		drawID, err := strconv.ParseUint(args[2], 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid draw id %q", args[2])
+		}
+		inserted, err := service.BackfillDraw(ctx, gameType, uint(drawID))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Draw %d: %d new results\n", drawID, inserted)
+		return nil
+	}
+
+	from, err := time.ParseInLocation(time.DateOnly, args[1], rules.Location())
+	if err != nil {
+		return fmt.Errorf("invalid from date %q", args[1])
+	}
+	to := time.Now()
+	if len(args) == 3 {
+		to, err = time.ParseInLocation(time.DateOnly, args[2], rules.Location())
+		if err != nil {
+			return fmt.Errorf("invalid to date %q", args[2])
+		}
+	}
+	if to.Before(from) {
+		return errors.New("to date is before from date")
+	}
+
+	inserted, err := service.BackfillResults(ctx, gameType, from, to)
+	if err != nil {
+		return fmt.Errorf("backfill stopped after %d new results with the circuit breaker %s, run it again to resume: %w",
+			inserted, breaker.State(), err)
+	}
+	fmt.Printf("Backfill of %s complete: %d new results\n", gameType, inserted)
+	return nil
+}
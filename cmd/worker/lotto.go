@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"lotto-notifications/internal/config"
+	"lotto-notifications/pkg/lotto"
+)
+
+// breakerReportInterval is how often the state of a breaker that is not closed is logged
+const breakerReportInterval = time.Minute
+
+// newLottoClient returns the client and its circuit breaker, kept to report the breaker's state
+func newLottoClient(cfg *config.Config) (lotto.Client, *lotto.CircuitBreaker, error) {
+	retry := lotto.DefaultRetryPolicy
+	retry.MaxAttempts = cfg.Lotto.MaxAttempts
+
+	// one limiter and breaker for the process, every worker goes through the same client
+	breaker := lotto.NewCircuitBreaker(cfg.Lotto.BreakerThreshold, cfg.Lotto.BreakerCooldown)
+	opts := []lotto.Option{
+		lotto.WithBaseURL(cfg.Lotto.BaseURL),
+		lotto.WithTimeout(cfg.Lotto.Timeout),
+		lotto.WithUserAgent(cfg.Lotto.UserAgent),
+		lotto.WithRetryPolicy(retry),
+		lotto.WithLimiter(lotto.NewLimiter(cfg.Lotto.RateLimit, cfg.Lotto.RateBurst)),
+		lotto.WithCircuitBreaker(breaker),
+	}
+
+	if cfg.Lotto.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.Lotto.ProxyURL)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid lotto proxy url: %w", err)
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = http.ProxyURL(proxyURL)
+		opts = append(opts, lotto.WithHTTPClient(&http.Client{Transport: transport}))
+	}
+
+	return lotto.NewClient(cfg.LottoAPIKey, opts...), breaker, nil
+}
+
+// reportBreaker logs the breaker's state every breakerReportInterval while it is not closed,
+// so a lotto API outage keeps showing up in the logs and not only when the state changes
+func reportBreaker(ctx context.Context, breaker *lotto.CircuitBreaker) {
+	ticker := time.NewTicker(breakerReportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if state := breaker.State(); state != lotto.CircuitClosed {
+			slog.Warn("Lotto API circuit breaker is not closed", "state", state)
+		}
+	}
+}
@@ -2,16 +2,23 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"log/slog"
 	"os"
 	"os/signal"
 	"sync"
 	"syscall"
+	_ "time/tzdata"
 
+	"lotto-notifications/internal/alerts"
 	"lotto-notifications/internal/config"
 	"lotto-notifications/internal/database"
 	"lotto-notifications/internal/logging"
+	"lotto-notifications/internal/notifier"
+	"lotto-notifications/internal/outbox"
+	"lotto-notifications/internal/prizes"
+	"lotto-notifications/internal/reminders"
 	"lotto-notifications/internal/repository"
 	"lotto-notifications/internal/service"
 	"lotto-notifications/internal/worker"
@@ -26,6 +33,14 @@ func main() {
 
 	logging.Init(cfg.Environment)
 
+	if len(os.Args) > 1 {
+		if err := runCommand(cfg, os.Args[1], os.Args[2:]); err != nil {
+			slog.Error("Command failed", "command", os.Args[1], "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	err = database.Initialize(cfg.DBPath)
 	if err != nil {
 		slog.Error("Failed to initialize database", "error", err)
@@ -39,13 +54,45 @@ func main() {
 		return
 	}
 
-	lottoClient := lotto.NewClient(cfg.LottoAPIKey)
+	lottoClient, breaker, err := newLottoClient(cfg)
+	if err != nil {
+		slog.Error("Failed to create lotto client", "error", err)
+		return
+	}
 	repo := repository.NewRepository(db)
-	service := service.NewService(lottoClient, repo)
+	channels, err := newNotifiers(cfg, repo)
+	if err != nil {
+		slog.Error("Failed to create notifiers", "error", err)
+		return
+	}
+	dispatcher := notifier.NewDispatcher(channels...)
+
+	var messengers []notifier.Messenger
+	for _, channel := range channels {
+		if m, ok := channel.(notifier.Messenger); ok {
+			messengers = append(messengers, m)
+		}
+	}
+	jackpotAlerts := alerts.NewJackpotAlerts(repo, messengers...)
+	reminderScheduler := reminders.NewScheduler(repo, reminders.Config{
+		StaleAfter: cfg.Reminders.StaleAfter,
+	}, messengers...)
+
+	service := service.NewService(lottoClient, repo, dispatcher.Names(), jackpotAlerts)
+	relay := outbox.NewRelay(repo, dispatcher, outbox.Config{
+		PollInterval: cfg.Outbox.PollInterval,
+		MaxAttempts:  cfg.Outbox.MaxAttempts,
+		RetryBackoff: cfg.Outbox.RetryBackoff,
+	})
+	prizePoller := prizes.NewPoller(service, repo, messengers...)
 
 	games, err := service.UpdateAllGames(context.Background())
+	if lotto.IsUnauthorized(err) {
+		slog.Error("Lotto API rejected the API key, check LOTTO_API_KEY", "error", err)
+		return
+	}
 	if err != nil {
-		slog.Error("Failed to update all games", "error", err)
+		slog.Error("Failed to update all games", "breaker", breaker.State(), "error", err)
 		return
 	}
 
@@ -53,6 +100,46 @@ func main() {
 	defer stop()
 
 	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		relay.Run(ctx)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		reminderScheduler.Run(ctx)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		jackpotAlerts.Run(ctx)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		prizePoller.Run(ctx)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		reportBreaker(ctx, breaker)
+	}()
+
+	for _, channel := range channels {
+		if r, ok := channel.(runner); ok {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r.Run(ctx)
+			}()
+		}
+	}
+
 	for _, game := range games {
 		w, err := worker.NewResultsWorker(game, repo, service)
 		if err != nil {
@@ -73,3 +160,18 @@ func main() {
 	wg.Wait()
 	slog.Info("Shutdown complete")
 }
+
+func runCommand(cfg *config.Config, command string, args []string) error {
+	switch command {
+	case "migrate":
+		return runMigrate(cfg, args)
+	case "webhooks":
+		return runWebhooks(cfg, args)
+	case "outbox":
+		return runOutbox(cfg, args)
+	case "backfill":
+		return runBackfill(cfg, args)
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"lotto-notifications/internal/config"
+	"lotto-notifications/internal/database"
+)
+
+// runMigrate handles `worker migrate <command>`
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) != 1 || !slices.Contains(database.MigrationCommands, args[0]) {
+		return fmt.Errorf("usage: worker migrate <%s>", strings.Join(database.MigrationCommands, "|"))
+	}
+
+	if err := database.Open(cfg.DBPath); err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	return database.Migrate(context.Background(), args[0])
+}
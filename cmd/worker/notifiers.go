@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"lotto-notifications/internal/config"
+	"lotto-notifications/internal/notifier"
+	"lotto-notifications/internal/notifier/discord"
+	"lotto-notifications/internal/notifier/email"
+	"lotto-notifications/internal/notifier/push"
+	"lotto-notifications/internal/notifier/slack"
+	"lotto-notifications/internal/notifier/telegram"
+	"lotto-notifications/internal/notifier/webhook"
+	"lotto-notifications/internal/repository"
+)
+
+// runner is implemented by channels that also need a background loop, e.g. to receive commands
+type runner interface {
+	Run(ctx context.Context)
+}
+
+// newNotifiers creates every channel that is enabled in the config
+func newNotifiers(cfg *config.Config, repo repository.Repository) ([]notifier.Notifier, error) {
+	pushConfig := push.Config{
+		JackpotThreshold: cfg.Push.JackpotThreshold,
+		ClickURL:         cfg.Push.ClickURL,
+	}
+	channels := []notifier.Notifier{
+		notifier.NewLogNotifier(repo),
+		webhook.New(repo),
+		push.NewNtfy(pushConfig, repo),
+		push.NewGotify(pushConfig, repo),
+	}
+
+	if cfg.SMTP.Host != "" {
+		emailNotifier, err := email.New(email.Config{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.SMTP.From,
+			TLS:      email.TLSMode(cfg.SMTP.TLS),
+		}, repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create email notifier: %w", err)
+		}
+		channels = append(channels, emailNotifier)
+	}
+
+	if cfg.Telegram.BotToken != "" {
+		channels = append(channels, telegram.New(cfg.Telegram.APIURL, cfg.Telegram.BotToken, repo, cfg.Reminders.DefaultOffsets))
+	}
+
+	if cfg.SlackWebhookURL != "" {
+		channels = append(channels, slack.New(cfg.SlackWebhookURL))
+	}
+	if cfg.DiscordWebhookURL != "" {
+		channels = append(channels, discord.New(cfg.DiscordWebhookURL))
+	}
+
+	return channels, nil
+}
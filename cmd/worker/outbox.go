@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"lotto-notifications/internal/config"
+	"lotto-notifications/internal/database"
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/repository"
+)
+
+const outboxUsage = "usage: worker outbox <pending|dead|retry ID>"
+
+// runOutbox handles `worker outbox <command>`
+func runOutbox(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(outboxUsage)
+	}
+
+	if err := database.Initialize(cfg.DBPath); err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer database.Close()
+
+	db, err := database.GetDB()
+	if err != nil {
+		return err
+	}
+	repo := repository.NewRepository(db)
+	ctx := context.Background()
+
+	switch {
+	case (args[0] == "pending" || args[0] == "dead") && len(args) == 1:
+		messages, err := repo.GetOutboxMessages(ctx, models.OutboxStatus(args[0]))
+		if err != nil {
+			return fmt.Errorf("failed to get outbox messages: %w", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tCHANNEL\tTARGET\tGAME\tATTEMPTS\tNEXT ATTEMPT\tERROR")
+		for _, msg := range messages {
+			target := "-"
+			if msg.Target != nil {
+				target = *msg.Target
+			}
+			lastError := ""
+			if msg.LastError != nil {
+				lastError = *msg.LastError
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%s\t%s\n",
+				msg.ID, msg.Channel, target, msg.GameType, msg.Attempts,
+				msg.NextAttemptAt.Format("2006-01-02 15:04:05"), lastError)
+		}
+		return w.Flush()
+	case args[0] == "retry" && len(args) == 2:
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid message id %q", args[1])
+		}
+		if err := repo.RequeueOutboxMessage(ctx, id); err != nil {
+			return fmt.Errorf("failed to requeue message: %w", err)
+		}
+		fmt.Printf("Requeued message %d\n", id)
+		return nil
+	default:
+		return errors.New(outboxUsage)
+	}
+}
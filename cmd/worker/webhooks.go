@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"lotto-notifications/internal/config"
+	"lotto-notifications/internal/database"
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/repository"
+)
+
+const webhooksUsage = "usage: worker webhooks <add URL SECRET|list|remove ID|deliveries ID>"
+
+// runWebhooks handles `worker webhooks <command>`
+func runWebhooks(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(webhooksUsage)
+	}
+
+	if err := database.Initialize(cfg.DBPath); err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer database.Close()
+
+	db, err := database.GetDB()
+	if err != nil {
+		return err
+	}
+	repo := repository.NewRepository(db)
+	ctx := context.Background()
+
+	switch {
+	case args[0] == "add" && len(args) == 3:
+		webhook, err := repo.CreateWebhook(ctx, models.Webhook{URL: args[1], Secret: args[2], Active: true})
+		if err != nil {
+			return fmt.Errorf("failed to create webhook: %w", err)
+		}
+		fmt.Printf("Created webhook %d\n", webhook.ID)
+		return nil
+	case args[0] == "list" && len(args) == 1:
+		webhooks, err := repo.GetWebhooks(ctx, false)
+		if err != nil {
+			return fmt.Errorf("failed to get webhooks: %w", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tACTIVE\tURL")
+		for _, webhook := range webhooks {
+			fmt.Fprintf(w, "%d\t%t\t%s\n", webhook.ID, webhook.Active, webhook.URL)
+		}
+		return w.Flush()
+	case args[0] == "remove" && len(args) == 2:
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid webhook id %q", args[1])
+		}
+		return repo.DeleteWebhook(ctx, id)
+	case args[0] == "deliveries" && len(args) == 2:
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid webhook id %q", args[1])
+		}
+		deliveries, err := repo.GetWebhookDeliveries(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get deliveries: %w", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "DELIVERY\tTIME\tSTATUS\tDURATION\tERROR")
+		for _, delivery := range deliveries {
+			status, deliveryErr := "-", ""
+			if delivery.StatusCode != nil {
+				status = strconv.Itoa(*delivery.StatusCode)
+			}
+			if delivery.Error != nil {
+				deliveryErr = *delivery.Error
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%dms\t%s\n",
+				delivery.DeliveryID, delivery.CreatedAt.Format("2006-01-02 15:04:05"), status, delivery.DurationMS, deliveryErr)
+		}
+		return w.Flush()
+	default:
+		return errors.New(webhooksUsage)
+	}
+}
@@ -0,0 +1,185 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/notifier"
+	"lotto-notifications/internal/repository"
+	"lotto-notifications/internal/service"
+)
+
+// JackpotAlerts is an observer that messages the subscribers whose jackpot threshold a game update
+// crossed. Every alert fires once per jackpot run: it is reset when the jackpot drops, i.e. it was won,
+// and fires again when the next run crosses the threshold.
+// Updates are only queued by GameUpdated, the alerts are evaluated and sent by Run.
+type JackpotAlerts interface {
+	service.GameObserver
+	Run(ctx context.Context)
+}
+
+type jackpotAlerts struct {
+	repo       repository.Repository
+	messengers map[models.ChannelType]notifier.Messenger
+
+	mu      sync.Mutex
+	pending map[models.GameType]models.Game
+	wake    chan struct{}
+}
+
+func NewJackpotAlerts(repo repository.Repository, messengers ...notifier.Messenger) JackpotAlerts {
+	byType := make(map[models.ChannelType]notifier.Messenger, len(messengers))
+	for _, messenger := range messengers {
+		byType[messenger.ChannelType()] = messenger
+	}
+	return &jackpotAlerts{
+		repo:       repo,
+		messengers: byType,
+		pending:    map[models.GameType]models.Game{},
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// GameUpdated queues the game for Run without blocking,
+// a newer update replaces one of the same game that was not evaluated yet
+func (a *jackpotAlerts) GameUpdated(ctx context.Context, game models.Game) {
+	a.mu.Lock()
+	a.pending[game.GameType] = game
+	a.mu.Unlock()
+
+	select {
+	case a.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run evaluates the queued game updates until the context is cancelled
+func (a *jackpotAlerts) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-a.wake:
+		}
+
+		for _, game := range a.takePending() {
+			if ctx.Err() != nil {
+				return
+			}
+			a.evaluate(ctx, game)
+		}
+	}
+}
+
+func (a *jackpotAlerts) takePending() []models.Game {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	games := make([]models.Game, 0, len(a.pending))
+	for _, game := range a.pending {
+		games = append(games, game)
+	}
+	clear(a.pending)
+	return games
+}
+
+// evaluate fires the alerts of the game whose threshold the jackpot crossed and resets the won ones
+func (a *jackpotAlerts) evaluate(ctx context.Context, game models.Game) {
+	// a missing or zero jackpot is a bad response, not a won jackpot
+	if game.ClosestPrizeValue == nil || *game.ClosestPrizeValue <= 0 {
+		return
+	}
+	jackpot := *game.ClosestPrizeValue
+
+	alerts, err := a.repo.GetJackpotAlertsByGame(ctx, game.GameType)
+	if err != nil {
+		slog.Error("Failed to get jackpot alerts", "game", game.GameType, "error", err)
+		return
+	}
+
+	for _, alert := range alerts {
+		if alert.NotifiedAt != nil && alert.NotifiedValue != nil && jackpot < *alert.NotifiedValue {
+			slog.Info("Jackpot dropped, resetting alert",
+				"game", game.GameType,
+				"alertID", alert.ID,
+				"notifiedValue", *alert.NotifiedValue,
+				"jackpot", jackpot,
+			)
+			if err := a.repo.UpdateJackpotAlertState(ctx, alert.ID, nil, nil); err != nil {
+				slog.Error("Failed to reset jackpot alert", "alertID", alert.ID, "error", err)
+				continue
+			}
+			alert.NotifiedAt = nil
+		}
+
+		if alert.NotifiedAt != nil || jackpot < alert.Threshold {
+			continue
+		}
+
+		if err := a.fire(ctx, game, alert); err != nil {
+			// the alert stays armed and is retried on the next update
+			slog.Error("Failed to send jackpot alert",
+				"game", game.GameType,
+				"alertID", alert.ID,
+				"subscriberID", alert.SubscriberID,
+				"error", err,
+			)
+			continue
+		}
+
+		now := time.Now()
+		if err := a.repo.UpdateJackpotAlertState(ctx, alert.ID, &now, &jackpot); err != nil {
+			slog.Error("Failed to save jackpot alert state", "alertID", alert.ID, "error", err)
+		}
+	}
+}
+
+// fire messages every channel of the subscriber that can be messaged,
+// it fails only when none of them was reached
+func (a *jackpotAlerts) fire(ctx context.Context, game models.Game, alert models.JackpotAlert) error {
+	channels, err := a.repo.GetSubscriberChannels(ctx, alert.SubscriberID)
+	if err != nil {
+		return fmt.Errorf("failed to get subscriber channels: %w", err)
+	}
+
+	msg := jackpotMessage(game, alert)
+	var errs []error
+	sent := 0
+	for _, channel := range channels {
+		messenger, ok := a.messengers[channel.Type]
+		if !ok {
+			continue
+		}
+		if err := messenger.Send(ctx, channel, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s channel %d: %w", channel.Type, channel.ID, err))
+			continue
+		}
+		sent++
+	}
+
+	if sent == 0 && len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if len(errs) > 0 {
+		slog.Warn("Jackpot alert not delivered to every channel", "alertID", alert.ID, "error", errors.Join(errs...))
+	}
+	slog.Info("Sent jackpot alert", "game", game.GameType, "subscriberID", alert.SubscriberID, "channels", sent)
+	return nil
+}
+
+func jackpotMessage(game models.Game, alert models.JackpotAlert) notifier.Message {
+	text := fmt.Sprintf("The %s jackpot is %s, above your alert of %s.",
+		game.GameType, notifier.FormatPLN(*game.ClosestPrizeValue), notifier.FormatPLN(alert.Threshold))
+	if game.NextDrawDate != nil {
+		text += "\nNext draw: " + notifier.FormatDate(*game.NextDrawDate)
+	}
+	return notifier.Message{
+		Title:  fmt.Sprintf("%s jackpot %s", game.GameType, notifier.FormatPLN(*game.ClosestPrizeValue)),
+		Text:   text,
+		Urgent: true,
+	}
+}
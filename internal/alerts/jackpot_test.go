@@ -0,0 +1,111 @@
+package alerts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/notifier"
+	"lotto-notifications/internal/repository"
+	"lotto-notifications/internal/repository/repositorytest"
+)
+
+// fakeMessenger hands every message to sent, blocking until the test receives it
+type fakeMessenger struct {
+	sent chan notifier.Message
+}
+
+func (m *fakeMessenger) ChannelType() models.ChannelType {
+	return models.ChannelTypeTelegram
+}
+
+func (m *fakeMessenger) Send(ctx context.Context, channel models.SubscriberChannel, msg notifier.Message) error {
+	select {
+	case m.sent <- msg:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// newAlert creates a subscriber with a telegram channel and a Lotto alert at the threshold
+func newAlert(t *testing.T, repo repository.Repository, threshold float64) {
+	t.Helper()
+	ctx := context.Background()
+	subscriber, err := repo.CreateSubscriber(ctx, models.Subscriber{Name: "Ala"})
+	if err != nil {
+		t.Fatalf("CreateSubscriber() error = %v", err)
+	}
+	channel := models.SubscriberChannel{SubscriberID: subscriber.ID, Type: models.ChannelTypeTelegram, Address: "42"}
+	if _, err := repo.AddSubscriberChannel(ctx, channel); err != nil {
+		t.Fatalf("AddSubscriberChannel() error = %v", err)
+	}
+	if _, err := repo.SetJackpotAlert(ctx, subscriber.ID, models.GameTypeLotto, threshold); err != nil {
+		t.Fatalf("SetJackpotAlert() error = %v", err)
+	}
+}
+
+func lottoGame(jackpot float64) models.Game {
+	return models.Game{GameType: models.GameTypeLotto, ClosestPrizeValue: &jackpot}
+}
+
+func TestGameUpdatedDoesNotBlock(t *testing.T) {
+	repo := repositorytest.New(t)
+	newAlert(t, repo, 10_000_000)
+	messenger := &fakeMessenger{sent: make(chan notifier.Message)}
+	a := NewJackpotAlerts(repo, messenger)
+
+	// nobody receives from the messenger yet, evaluating the alerts here would block
+	a.GameUpdated(context.Background(), lottoGame(12_000_000))
+	a.GameUpdated(context.Background(), lottoGame(15_000_000))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		a.Run(ctx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	select {
+	case msg := <-messenger.sent:
+		if msg.Title != "Lotto jackpot 15 000 000 PLN" {
+			t.Errorf("title = %q, want the latest jackpot", msg.Title)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("alert was not sent")
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	repo := repositorytest.New(t)
+	newAlert(t, repo, 10_000_000)
+	messenger := &fakeMessenger{sent: make(chan notifier.Message, 10)}
+	a := NewJackpotAlerts(repo, messenger).(*jackpotAlerts)
+
+	steps := []struct {
+		jackpot  float64
+		wantSent bool
+	}{
+		{jackpot: 5_000_000},
+		{jackpot: 12_000_000, wantSent: true},
+		{jackpot: 15_000_000},
+		{jackpot: 0},
+		{jackpot: 2_000_000},
+		{jackpot: 11_000_000, wantSent: true},
+	}
+	for _, step := range steps {
+		a.evaluate(context.Background(), lottoGame(step.jackpot))
+		sent := len(messenger.sent) > 0
+		if sent != step.wantSent {
+			t.Errorf("jackpot %.0f: sent = %t, want %t", step.jackpot, sent, step.wantSent)
+		}
+		for len(messenger.sent) > 0 {
+			<-messenger.sent
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/repository"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+type handler struct {
+	repo repository.Repository
+}
+
+// NewHandler returns the read-only JSON API over the stored games and results
+func NewHandler(repo repository.Repository) http.Handler {
+	h := &handler{repo: repo}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /games", h.getGames)
+	mux.HandleFunc("GET /games/{type}", h.getGame)
+	mux.HandleFunc("GET /games/{type}/results", h.getResults)
+	mux.HandleFunc("GET /games/{type}/results/latest", h.getLatestResult)
+	mux.HandleFunc("GET /games/{type}/jackpot", h.getJackpotHistory)
+	return mux
+}
+
+func (h *handler) getGames(w http.ResponseWriter, r *http.Request) {
+	games, err := h.repo.GetGames(r.Context(), false)
+	if err != nil {
+		writeInternalError(w, "Failed to get games", err)
+		return
+	}
+
+	response := make([]gameResponse, len(games))
+	for idx, game := range games {
+		response[idx] = newGameResponse(game)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *handler) getGame(w http.ResponseWriter, r *http.Request) {
+	game, err := h.repo.GetGame(r.Context(), r.PathValue("type"))
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "game not found")
+		return
+	}
+	if err != nil {
+		writeInternalError(w, "Failed to get game", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newGameResponse(game))
+}
+
+func (h *handler) getResults(w http.ResponseWriter, r *http.Request) {
+	gameType := r.PathValue("type")
+	if !h.gameExists(w, r, gameType) {
+		return
+	}
+
+	query, err := parseResultsQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, err := h.repo.GetResultsPage(r.Context(), gameType, query)
+	if err != nil {
+		writeInternalError(w, "Failed to get results", err)
+		return
+	}
+
+	response := resultsPageResponse{Results: make([]resultResponse, len(results))}
+	for idx, result := range results {
+		response.Results[idx] = newResultResponse(result)
+	}
+	if len(results) == query.Limit {
+		cursor := strconv.FormatUint(uint64(results[len(results)-1].DrawID), 10)
+		response.NextCursor = &cursor
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *handler) getLatestResult(w http.ResponseWriter, r *http.Request) {
+	gameType := r.PathValue("type")
+	if !h.gameExists(w, r, gameType) {
+		return
+	}
+
+	result, err := h.repo.GetNewestResult(r.Context(), gameType)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "no results found")
+		return
+	}
+	if err != nil {
+		writeInternalError(w, "Failed to get latest result", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newResultResponse(result))
+}
+
+func (h *handler) getJackpotHistory(w http.ResponseWriter, r *http.Request) {
+	gameType := r.PathValue("type")
+	if !h.gameExists(w, r, gameType) {
+		return
+	}
+
+	from, to, err := parseRange(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	timeline, err := h.repo.GetJackpotTimeline(r.Context(), models.GameType(gameType), from, to)
+	if err != nil {
+		writeInternalError(w, "Failed to get jackpot timeline", err)
+		return
+	}
+	hits, err := h.repo.GetJackpotHits(r.Context(), models.GameType(gameType), from, to)
+	if err != nil {
+		writeInternalError(w, "Failed to get jackpot hits", err)
+		return
+	}
+
+	response := jackpotHistoryResponse{
+		Timeline: make([]snapshotResponse, len(timeline)),
+		Hits:     make([]jackpotHitResponse, len(hits)),
+	}
+	for idx, snapshot := range timeline {
+		response.Timeline[idx] = newSnapshotResponse(snapshot)
+	}
+	for idx, hit := range hits {
+		response.Hits[idx] = newJackpotHitResponse(hit)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// gameExists writes a not found response for unknown games so they are not mistaken for games without results
+func (h *handler) gameExists(w http.ResponseWriter, r *http.Request, gameType string) bool {
+	_, err := h.repo.GetGame(r.Context(), gameType)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "game not found")
+		return false
+	}
+	if err != nil {
+		writeInternalError(w, "Failed to get game", err)
+		return false
+	}
+	return true
+}
+
+func parseResultsQuery(r *http.Request) (repository.ResultsQuery, error) {
+	params := r.URL.Query()
+	query := repository.ResultsQuery{Limit: defaultLimit}
+
+	var err error
+	query.From, query.To, err = parseRange(r)
+	if err != nil {
+		return repository.ResultsQuery{}, err
+	}
+	if value := params.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxLimit {
+			return repository.ResultsQuery{}, errors.New("invalid limit, expected 1-" + strconv.Itoa(maxLimit))
+		}
+		query.Limit = limit
+	}
+	if value := params.Get("cursor"); value != "" {
+		cursor, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return repository.ResultsQuery{}, errors.New("invalid cursor")
+		}
+		drawID := uint(cursor)
+		query.AfterDrawID = &drawID
+	}
+
+	return query, nil
+}
+
+// parseRange reads the optional from and to query parameters
+func parseRange(r *http.Request) (*time.Time, *time.Time, error) {
+	params := r.URL.Query()
+	var from, to *time.Time
+
+	if value := params.Get("from"); value != "" {
+		parsed, err := parseDate(value)
+		if err != nil {
+			return nil, nil, errors.New("invalid from, expected RFC 3339 or YYYY-MM-DD")
+		}
+		from = &parsed
+	}
+	if value := params.Get("to"); value != "" {
+		parsed, err := parseDate(value)
+		if err != nil {
+			return nil, nil, errors.New("invalid to, expected RFC 3339 or YYYY-MM-DD")
+		}
+		// a plain date includes the whole day
+		if len(value) == len(time.DateOnly) {
+			parsed = parsed.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		to = &parsed
+	}
+	return from, to, nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		slog.Error("Failed to encode response", "error", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
+
+func writeInternalError(w http.ResponseWriter, message string, err error) {
+	slog.Error(message, "error", err)
+	writeError(w, http.StatusInternalServerError, "internal server error")
+}
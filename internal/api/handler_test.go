@@ -0,0 +1,299 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/repository/repositorytest"
+)
+
+// newTestHandler returns the API over Lotto draws 7001-7005, drawn at 20:00 UTC on the 1st to the 5th of October 2026
+func newTestHandler(t *testing.T) http.Handler {
+	t.Helper()
+	repo := repositorytest.New(t)
+
+	var draws []models.Draw
+	for day := 1; day <= 5; day++ {
+		drawID := uint(7000 + day)
+		drawDate := time.Date(2026, time.October, day, 20, 0, 0, 0, time.UTC)
+		draws = append(draws, models.Draw{
+			DrawID:   drawID,
+			GameType: models.GameTypeLotto,
+			DrawDate: drawDate,
+			Results: []models.Result{
+				{DrawID: drawID, GameType: models.GameTypeLotto, DrawDate: drawDate, Results: models.IntSlice{1, 2, 3, 4, 5, day + 6}},
+			},
+		})
+	}
+	if _, err := repo.InsertDraws(context.Background(), draws); err != nil {
+		t.Fatalf("InsertDraws() error = %v", err)
+	}
+	return NewHandler(repo)
+}
+
+// get serves the request and decodes a successful response into body
+func get(t *testing.T, h http.Handler, target string, body any) (int, errorResponse) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+
+	if rec.Code != http.StatusOK {
+		var errResp errorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+			t.Fatalf("GET %s: failed to decode error response %q: %v", target, rec.Body.String(), err)
+		}
+		return rec.Code, errResp
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), body); err != nil {
+		t.Fatalf("GET %s: failed to decode response %q: %v", target, rec.Body.String(), err)
+	}
+	return rec.Code, errorResponse{}
+}
+
+func drawIDs(page resultsPageResponse) []uint {
+	ids := make([]uint, len(page.Results))
+	for idx, result := range page.Results {
+		ids[idx] = result.DrawID
+	}
+	return ids
+}
+
+func TestGetResults(t *testing.T) {
+	h := newTestHandler(t)
+	cursor := func(value string) *string { return &value }
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+		wantError  string
+		wantIDs    []uint
+		wantCursor *string
+	}{
+		{
+			name:       "newest first",
+			target:     "/games/Lotto/results",
+			wantStatus: http.StatusOK,
+			wantIDs:    []uint{7005, 7004, 7003, 7002, 7001},
+		},
+		{
+			name:       "full page has a cursor",
+			target:     "/games/Lotto/results?limit=2",
+			wantStatus: http.StatusOK,
+			wantIDs:    []uint{7005, 7004},
+			wantCursor: cursor("7004"),
+		},
+		{
+			name:       "page after the cursor",
+			target:     "/games/Lotto/results?limit=2&cursor=7004",
+			wantStatus: http.StatusOK,
+			wantIDs:    []uint{7003, 7002},
+			wantCursor: cursor("7002"),
+		},
+		{
+			name:       "last page has no cursor",
+			target:     "/games/Lotto/results?limit=2&cursor=7002",
+			wantStatus: http.StatusOK,
+			wantIDs:    []uint{7001},
+		},
+		{
+			name:       "plain dates include the whole day",
+			target:     "/games/Lotto/results?from=2026-10-02&to=2026-10-03",
+			wantStatus: http.StatusOK,
+			wantIDs:    []uint{7003, 7002},
+		},
+		{
+			name:       "RFC 3339 range",
+			target:     "/games/Lotto/results?from=2026-10-04T00:00:00%2B02:00&to=2026-10-04T21:00:00Z",
+			wantStatus: http.StatusOK,
+			wantIDs:    []uint{7004},
+		},
+		{
+			name:       "no results in the range",
+			target:     "/games/Lotto/results?from=2026-11-01",
+			wantStatus: http.StatusOK,
+			wantIDs:    []uint{},
+		},
+		{
+			name:       "game without results",
+			target:     "/games/MiniLotto/results",
+			wantStatus: http.StatusOK,
+			wantIDs:    []uint{},
+		},
+		{
+			name:       "zero limit",
+			target:     "/games/Lotto/results?limit=0",
+			wantStatus: http.StatusBadRequest,
+			wantError:  "invalid limit, expected 1-100",
+		},
+		{
+			name:       "limit over the maximum",
+			target:     "/games/Lotto/results?limit=101",
+			wantStatus: http.StatusBadRequest,
+			wantError:  "invalid limit, expected 1-100",
+		},
+		{
+			name:       "limit not a number",
+			target:     "/games/Lotto/results?limit=ten",
+			wantStatus: http.StatusBadRequest,
+			wantError:  "invalid limit, expected 1-100",
+		},
+		{
+			name:       "negative cursor",
+			target:     "/games/Lotto/results?cursor=-1",
+			wantStatus: http.StatusBadRequest,
+			wantError:  "invalid cursor",
+		},
+		{
+			name:       "invalid from",
+			target:     "/games/Lotto/results?from=yesterday",
+			wantStatus: http.StatusBadRequest,
+			wantError:  "invalid from, expected RFC 3339 or YYYY-MM-DD",
+		},
+		{
+			name:       "invalid to",
+			target:     "/games/Lotto/results?to=2026-13-01",
+			wantStatus: http.StatusBadRequest,
+			wantError:  "invalid to, expected RFC 3339 or YYYY-MM-DD",
+		},
+		{
+			name:       "unknown game",
+			target:     "/games/Keno/results",
+			wantStatus: http.StatusNotFound,
+			wantError:  "game not found",
+		},
+		{
+			name:       "unknown game is reported before a bad query",
+			target:     "/games/Keno/results?limit=0",
+			wantStatus: http.StatusNotFound,
+			wantError:  "game not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var page resultsPageResponse
+			status, errResp := get(t, h, tt.target, &page)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if errResp.Error != tt.wantError {
+				t.Errorf("error = %q, want %q", errResp.Error, tt.wantError)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if got := drawIDs(page); !slices.Equal(got, tt.wantIDs) {
+				t.Errorf("draw IDs = %v, want %v", got, tt.wantIDs)
+			}
+			switch {
+			case tt.wantCursor == nil && page.NextCursor != nil:
+				t.Errorf("next_cursor = %q, want none", *page.NextCursor)
+			case tt.wantCursor != nil && (page.NextCursor == nil || *page.NextCursor != *tt.wantCursor):
+				t.Errorf("next_cursor = %v, want %q", page.NextCursor, *tt.wantCursor)
+			}
+		})
+	}
+}
+
+func TestGetResultsWalksPages(t *testing.T) {
+	h := newTestHandler(t)
+
+	var got []uint
+	target := "/games/Lotto/results?limit=2"
+	for range 10 {
+		var page resultsPageResponse
+		if status, errResp := get(t, h, target, &page); status != http.StatusOK {
+			t.Fatalf("GET %s: status = %d, error = %q", target, status, errResp.Error)
+		}
+		got = append(got, drawIDs(page)...)
+		if page.NextCursor == nil {
+			break
+		}
+		target = "/games/Lotto/results?limit=2&cursor=" + *page.NextCursor
+	}
+
+	if want := []uint{7005, 7004, 7003, 7002, 7001}; !slices.Equal(got, want) {
+		t.Errorf("walked draw IDs %v, want %v", got, want)
+	}
+}
+
+func TestGetLatestResult(t *testing.T) {
+	h := newTestHandler(t)
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+		wantError  string
+		wantDrawID uint
+	}{
+		{
+			name:       "newest draw",
+			target:     "/games/Lotto/results/latest",
+			wantStatus: http.StatusOK,
+			wantDrawID: 7005,
+		},
+		{
+			name:       "game without results",
+			target:     "/games/MiniLotto/results/latest",
+			wantStatus: http.StatusNotFound,
+			wantError:  "no results found",
+		},
+		{
+			name:       "unknown game",
+			target:     "/games/Keno/results/latest",
+			wantStatus: http.StatusNotFound,
+			wantError:  "game not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result resultResponse
+			status, errResp := get(t, h, tt.target, &result)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if errResp.Error != tt.wantError {
+				t.Errorf("error = %q, want %q", errResp.Error, tt.wantError)
+			}
+			if result.DrawID != tt.wantDrawID {
+				t.Errorf("draw_id = %d, want %d", result.DrawID, tt.wantDrawID)
+			}
+		})
+	}
+}
+
+func TestGetGame(t *testing.T) {
+	h := newTestHandler(t)
+
+	var game gameResponse
+	if status, errResp := get(t, h, "/games/Lotto", &game); status != http.StatusOK {
+		t.Fatalf("status = %d, error = %q, want %d", status, errResp.Error, http.StatusOK)
+	}
+	if game.Type != models.GameTypeLotto {
+		t.Errorf("type = %q, want %q", game.Type, models.GameTypeLotto)
+	}
+
+	status, errResp := get(t, h, "/games/Keno", &game)
+	if status != http.StatusNotFound || errResp.Error != "game not found" {
+		t.Errorf("unknown game: status = %d, error = %q, want %d game not found", status, errResp.Error, http.StatusNotFound)
+	}
+}
+
+func TestGetJackpotHistoryRejectsInvalidRange(t *testing.T) {
+	h := newTestHandler(t)
+
+	var history jackpotHistoryResponse
+	status, errResp := get(t, h, "/games/Lotto/jackpot?from=last-week", &history)
+	if status != http.StatusBadRequest || errResp.Error != "invalid from, expected RFC 3339 or YYYY-MM-DD" {
+		t.Errorf("status = %d, error = %q, want %d invalid from", status, errResp.Error, http.StatusBadRequest)
+	}
+}
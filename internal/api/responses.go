@@ -0,0 +1,103 @@
+package api
+
+import (
+	"time"
+
+	"lotto-notifications/internal/models"
+)
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type gameResponse struct {
+	Type              models.GameType `json:"type"`
+	NextDrawDate      *time.Time      `json:"next_draw_date"`
+	ClosestPrizeValue *float64        `json:"closest_prize_value"`
+	Draws             *string         `json:"draws"`
+	CouponPrice       *string         `json:"coupon_price"`
+	ClosestPrizePool  *string         `json:"closest_prize_pool"`
+	TiedTo            *string         `json:"tied_to"`
+}
+
+type resultResponse struct {
+	DrawID         uint            `json:"draw_id"`
+	GameType       models.GameType `json:"game_type"`
+	DrawDate       time.Time       `json:"draw_date"`
+	Results        []int           `json:"results"`
+	SpecialResults []int           `json:"special_results"`
+}
+
+type resultsPageResponse struct {
+	Results    []resultResponse `json:"results"`
+	NextCursor *string          `json:"next_cursor"`
+}
+
+type snapshotResponse struct {
+	NextDrawDate      *time.Time `json:"next_draw_date"`
+	ClosestPrizeValue *float64   `json:"closest_prize_value"`
+	ClosestPrizePool  *string    `json:"closest_prize_pool"`
+	CouponPrice       *string    `json:"coupon_price"`
+	FetchedAt         time.Time  `json:"fetched_at"`
+}
+
+type jackpotHitResponse struct {
+	DrawDate    *time.Time `json:"draw_date"`
+	Jackpot     float64    `json:"jackpot"`
+	NextJackpot float64    `json:"next_jackpot"`
+	DetectedAt  time.Time  `json:"detected_at"`
+}
+
+type jackpotHistoryResponse struct {
+	Timeline []snapshotResponse   `json:"timeline"`
+	Hits     []jackpotHitResponse `json:"hits"`
+}
+
+func newGameResponse(game models.Game) gameResponse {
+	return gameResponse{
+		Type:              game.GameType,
+		NextDrawDate:      game.NextDrawDate,
+		ClosestPrizeValue: game.ClosestPrizeValue,
+		Draws:             game.Draws,
+		CouponPrice:       game.CouponPrice,
+		ClosestPrizePool:  game.ClosestPrizePool,
+		TiedTo:            game.TiedTo,
+	}
+}
+
+func newResultResponse(result models.Result) resultResponse {
+	return resultResponse{
+		DrawID:         result.DrawID,
+		GameType:       result.GameType,
+		DrawDate:       result.DrawDate,
+		Results:        nonNil(result.Results),
+		SpecialResults: nonNil(result.SpecialResults),
+	}
+}
+
+func newSnapshotResponse(snapshot models.GameSnapshot) snapshotResponse {
+	return snapshotResponse{
+		NextDrawDate:      snapshot.NextDrawDate,
+		ClosestPrizeValue: snapshot.ClosestPrizeValue,
+		ClosestPrizePool:  snapshot.ClosestPrizePool,
+		CouponPrice:       snapshot.CouponPrice,
+		FetchedAt:         snapshot.FetchedAt,
+	}
+}
+
+func newJackpotHitResponse(hit models.JackpotHit) jackpotHitResponse {
+	return jackpotHitResponse{
+		DrawDate:    hit.DrawDate,
+		Jackpot:     hit.Jackpot,
+		NextJackpot: hit.NextJackpot,
+		DetectedAt:  hit.DetectedAt,
+	}
+}
+
+// nonNil makes empty number lists encode as [] instead of null
+func nonNil(numbers []int) []int {
+	if numbers == nil {
+		return []int{}
+	}
+	return numbers
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/caarlos0/env/v11"
 	"github.com/joho/godotenv"
@@ -13,6 +14,74 @@ type Config struct {
 	Environment string `env:"ENVIRONMENT" envDefault:"development"`
 	DBPath      string `env:"DB_PATH" envDefault:"./data/database.sqlite"`
 	LottoAPIKey string `env:"LOTTO_API_KEY"`
+	APIAddr     string `env:"API_ADDR" envDefault:":8080"`
+
+	Lotto LottoConfig `envPrefix:"LOTTO_"`
+
+	SMTP     SMTPConfig     `envPrefix:"SMTP_"`
+	Telegram TelegramConfig `envPrefix:"TELEGRAM_"`
+
+	SlackWebhookURL   string `env:"SLACK_WEBHOOK_URL"`
+	DiscordWebhookURL string `env:"DISCORD_WEBHOOK_URL"`
+
+	Push PushConfig `envPrefix:"PUSH_"`
+
+	Outbox OutboxConfig `envPrefix:"OUTBOX_"`
+
+	Reminders RemindersConfig `envPrefix:"REMINDER_"`
+}
+
+// LottoConfig configures how the lotto API is reached, the key is LottoAPIKey
+type LottoConfig struct {
+	BaseURL   string        `env:"BASE_URL" envDefault:"https://developers.lotto.pl/api/open/v1"`
+	Timeout   time.Duration `env:"TIMEOUT" envDefault:"10s"`
+	UserAgent string        `env:"USER_AGENT" envDefault:"lotto-notifications"`
+	// ProxyURL routes the API requests through a proxy, HTTPS_PROXY is used when it is empty
+	ProxyURL string `env:"PROXY_URL"`
+
+	MaxAttempts int `env:"MAX_ATTEMPTS" envDefault:"4"`
+	// RateLimit is in requests per second and shared by all workers, zero disables it
+	RateLimit        float64       `env:"RATE_LIMIT" envDefault:"1"`
+	RateBurst        int           `env:"RATE_BURST" envDefault:"2"`
+	BreakerThreshold int           `env:"BREAKER_THRESHOLD" envDefault:"5"`
+	BreakerCooldown  time.Duration `env:"BREAKER_COOLDOWN" envDefault:"1m"`
+}
+
+// SMTPConfig configures the email channel, it is disabled when Host is empty
+type SMTPConfig struct {
+	Host     string `env:"HOST"`
+	Port     int    `env:"PORT" envDefault:"587"`
+	Username string `env:"USERNAME"`
+	Password string `env:"PASSWORD"`
+	From     string `env:"FROM"`
+	TLS      string `env:"TLS" envDefault:"starttls"` // none, starttls or tls
+}
+
+// PushConfig configures the ntfy and Gotify channels, the servers and tokens are set per subscriber
+type PushConfig struct {
+	JackpotThreshold float64 `env:"JACKPOT_THRESHOLD" envDefault:"20000000"`
+	ClickURL         string  `env:"CLICK_URL" envDefault:"https://www.lotto.pl"`
+}
+
+// OutboxConfig configures how queued notifications are delivered and retried
+type OutboxConfig struct {
+	PollInterval time.Duration `env:"POLL_INTERVAL" envDefault:"10s"`
+	MaxAttempts  int           `env:"MAX_ATTEMPTS" envDefault:"8"`
+	RetryBackoff time.Duration `env:"RETRY_BACKOFF" envDefault:"1m"`
+}
+
+// RemindersConfig configures the pre-draw reminders
+type RemindersConfig struct {
+	// DefaultOffsets are used when a subscriber turns reminders on without choosing when
+	DefaultOffsets []time.Duration `env:"DEFAULT_OFFSETS" envDefault:"3h,30m"`
+	// StaleAfter is how late a reminder may still be sent, e.g. after a restart
+	StaleAfter time.Duration `env:"STALE_AFTER" envDefault:"15m"`
+}
+
+// TelegramConfig configures the Telegram bot, it is disabled when BotToken is empty
+type TelegramConfig struct {
+	BotToken string `env:"BOT_TOKEN"`
+	APIURL   string `env:"API_URL" envDefault:"https://api.telegram.org"`
 }
 
 func LoadConfig() (*Config, error) {
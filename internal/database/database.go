@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -18,8 +19,23 @@ func GetDB() (*sqlx.DB, error) {
 	return db, nil
 }
 
-// Initialize sets up the database connection and creates the database if it doesn't exist
+// Initialize sets up the database connection, creates the database if it doesn't exist
+// and applies all pending migrations
 func Initialize(dbPath string) error {
+	if err := Open(dbPath); err != nil {
+		return err
+	}
+
+	if err := Migrate(context.Background(), "up"); err != nil {
+		Close()
+		return err
+	}
+
+	return nil
+}
+
+// Open sets up the database connection without touching the schema
+func Open(dbPath string) error {
 	// Ensure data directory exists
 	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
 		return fmt.Errorf("failed to create data directory: %w", err)
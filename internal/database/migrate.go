@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"lotto-notifications/internal/logging"
+
+	"github.com/pressly/goose/v3"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// MigrationCommands lists the goose commands that can be run against the embedded migrations
+var MigrationCommands = []string{"up", "down", "status", "redo"}
+
+// Migrate runs a goose command against the embedded migrations.
+// Applied versions are tracked by goose in the goose_db_version table.
+func Migrate(ctx context.Context, command string) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	goose.SetBaseFS(migrations)
+	goose.SetLogger(gooseLogger{})
+	if err := goose.SetDialect("sqlite3"); err != nil {
+		return fmt.Errorf("failed to set migration dialect: %w", err)
+	}
+
+	if err := goose.RunContext(ctx, command, db.DB, "migrations"); err != nil {
+		return fmt.Errorf("failed to run migration command %q: %w", command, err)
+	}
+
+	return nil
+}
+
+// gooseLogger forwards goose output to slog so migrations show up in the application logs
+type gooseLogger struct{}
+
+func (gooseLogger) Printf(format string, v ...any) {
+	slog.Info(strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+func (gooseLogger) Fatalf(format string, v ...any) {
+	slog.Log(context.Background(), logging.LevelFatal, strings.TrimSpace(fmt.Sprintf(format, v...)))
+	os.Exit(1)
+}
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS subscribers (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    name       TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS subscriber_channels (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    subscriber_id INTEGER NOT NULL REFERENCES subscribers(id),
+    type          TEXT NOT NULL,
+    address       TEXT NOT NULL,
+    token         TEXT DEFAULT NULL,
+    created_at    TIMESTAMP NOT NULL,
+    UNIQUE (subscriber_id, type, address)
+);
+CREATE INDEX IF NOT EXISTS idx_subscriber_channels_type ON subscriber_channels (type);
+
+CREATE TABLE IF NOT EXISTS tickets (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    subscriber_id   INTEGER NOT NULL REFERENCES subscribers(id),
+    game_type       TEXT NOT NULL REFERENCES games(type),
+    numbers         TEXT NOT NULL,
+    special_numbers TEXT DEFAULT NULL,
+    created_at      TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_tickets_subscriber_id ON tickets (subscriber_id);
+CREATE INDEX IF NOT EXISTS idx_tickets_game_type ON tickets (game_type);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_tickets_game_type;
+DROP INDEX IF EXISTS idx_tickets_subscriber_id;
+DROP TABLE IF EXISTS tickets;
+DROP INDEX IF EXISTS idx_subscriber_channels_type;
+DROP TABLE IF EXISTS subscriber_channels;
+DROP TABLE IF EXISTS subscribers;
+-- +goose StatementEnd
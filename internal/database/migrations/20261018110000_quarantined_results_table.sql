@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS quarantined_results (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    draw_id         INTEGER NOT NULL,
+    game_type       TEXT NOT NULL,
+    draw_date       TIMESTAMP NOT NULL,
+    results         TEXT NOT NULL,
+    special_results TEXT DEFAULT NULL,
+    reason          TEXT NOT NULL,
+    created_at      TIMESTAMP NOT NULL,
+    UNIQUE (draw_id, game_type)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS quarantined_results;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS jackpot_alerts (
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    subscriber_id  INTEGER NOT NULL REFERENCES subscribers(id),
+    game_type      TEXT NOT NULL REFERENCES games(type),
+    threshold      REAL NOT NULL,
+    notified_at    TIMESTAMP DEFAULT NULL,
+    notified_value REAL DEFAULT NULL,
+    created_at     TIMESTAMP NOT NULL,
+    UNIQUE (subscriber_id, game_type)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS jackpot_alerts;
+-- +goose StatementEnd
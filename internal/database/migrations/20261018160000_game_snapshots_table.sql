@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS game_snapshots (
+    id                  INTEGER PRIMARY KEY AUTOINCREMENT,
+    game_type           TEXT NOT NULL REFERENCES games(type),
+    next_draw_date      TIMESTAMP DEFAULT NULL,
+    closest_prize_value REAL DEFAULT NULL,
+    closest_prize_pool  TEXT DEFAULT NULL,
+    coupon_price        TEXT DEFAULT NULL,
+    fetched_at          TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_game_snapshots_game_type_fetched_at ON game_snapshots (game_type, fetched_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_game_snapshots_game_type_fetched_at;
+DROP TABLE IF EXISTS game_snapshots;
+-- +goose StatementEnd
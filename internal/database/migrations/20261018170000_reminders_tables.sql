@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS reminders (
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    subscriber_id  INTEGER NOT NULL REFERENCES subscribers(id),
+    game_type      TEXT NOT NULL REFERENCES games(type),
+    offset_minutes INTEGER NOT NULL,
+    created_at     TIMESTAMP NOT NULL,
+    UNIQUE (subscriber_id, game_type, offset_minutes)
+);
+CREATE TABLE IF NOT EXISTS pending_reminders (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    reminder_id INTEGER NOT NULL REFERENCES reminders(id),
+    draw_date   TIMESTAMP NOT NULL,
+    fire_at     TIMESTAMP NOT NULL,
+    status      TEXT NOT NULL DEFAULT 'pending',
+    updated_at  TIMESTAMP NOT NULL,
+    UNIQUE (reminder_id, draw_date)
+);
+CREATE INDEX IF NOT EXISTS idx_pending_reminders_status_fire_at ON pending_reminders (status, fire_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_pending_reminders_status_fire_at;
+DROP TABLE IF EXISTS pending_reminders;
+DROP TABLE IF EXISTS reminders;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS backfill_checkpoints (
+    game_type    TEXT PRIMARY KEY REFERENCES games(type),
+    from_date    TIMESTAMP NOT NULL,
+    to_date      TIMESTAMP NOT NULL,
+    next_page    INTEGER NOT NULL,
+    inserted     INTEGER NOT NULL DEFAULT 0,
+    completed_at TIMESTAMP DEFAULT NULL,
+    updated_at   TIMESTAMP NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS backfill_checkpoints;
+-- +goose StatementEnd
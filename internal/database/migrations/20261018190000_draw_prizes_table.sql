@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS draw_prizes (
+    draw_id    INTEGER NOT NULL,
+    game_type  TEXT NOT NULL REFERENCES games(type),
+    tier       INTEGER NOT NULL,
+    winners    INTEGER NOT NULL,
+    amount     REAL NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (draw_id, game_type, tier)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS draw_prizes;
+-- +goose StatementEnd
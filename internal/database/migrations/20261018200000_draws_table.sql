@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS draws (
+    draw_id                 INTEGER NOT NULL,
+    game_type               TEXT NOT NULL REFERENCES games(type),
+    draw_date               TIMESTAMP NOT NULL,
+    multiplier_value        INTEGER NOT NULL DEFAULT 0,
+    show_special_results    BOOLEAN NOT NULL DEFAULT FALSE,
+    is_new_eurojackpot_draw BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at              TIMESTAMP NOT NULL,
+    PRIMARY KEY (draw_id, game_type)
+);
+ALTER TABLE results ADD COLUMN parent_draw_id INTEGER DEFAULT NULL;
+ALTER TABLE results ADD COLUMN parent_game_type TEXT DEFAULT NULL;
+CREATE INDEX IF NOT EXISTS idx_results_parent ON results (parent_draw_id, parent_game_type);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_results_parent;
+ALTER TABLE results DROP COLUMN parent_game_type;
+ALTER TABLE results DROP COLUMN parent_draw_id;
+DROP TABLE IF EXISTS draws;
+-- +goose StatementEnd
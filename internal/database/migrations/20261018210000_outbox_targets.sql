@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE outbox ADD COLUMN parent_id INTEGER DEFAULT NULL REFERENCES outbox(id);
+ALTER TABLE outbox ADD COLUMN target TEXT DEFAULT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outbox_parent_target ON outbox (parent_id, target);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_outbox_parent_target;
+ALTER TABLE outbox DROP COLUMN target;
+ALTER TABLE outbox DROP COLUMN parent_id;
+-- +goose StatementEnd
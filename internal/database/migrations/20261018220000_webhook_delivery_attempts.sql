@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE webhook_deliveries_new (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    webhook_id  INTEGER NOT NULL REFERENCES webhooks(id),
+    delivery_id TEXT NOT NULL,
+    status_code INTEGER DEFAULT NULL,
+    error       TEXT DEFAULT NULL,
+    duration_ms INTEGER NOT NULL,
+    created_at  TIMESTAMP NOT NULL
+);
+INSERT INTO webhook_deliveries_new SELECT * FROM webhook_deliveries;
+DROP INDEX IF EXISTS idx_webhook_deliveries_webhook_id;
+DROP TABLE webhook_deliveries;
+ALTER TABLE webhook_deliveries_new RENAME TO webhook_deliveries;
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_delivery_id ON webhook_deliveries (delivery_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE TABLE webhook_deliveries_old (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    webhook_id  INTEGER NOT NULL REFERENCES webhooks(id),
+    delivery_id TEXT NOT NULL UNIQUE,
+    status_code INTEGER DEFAULT NULL,
+    error       TEXT DEFAULT NULL,
+    duration_ms INTEGER NOT NULL,
+    created_at  TIMESTAMP NOT NULL
+);
+INSERT INTO webhook_deliveries_old
+    SELECT * FROM webhook_deliveries WHERE id IN (SELECT MAX(id) FROM webhook_deliveries GROUP BY delivery_id);
+DROP INDEX IF EXISTS idx_webhook_deliveries_delivery_id;
+DROP INDEX IF EXISTS idx_webhook_deliveries_webhook_id;
+DROP TABLE webhook_deliveries;
+ALTER TABLE webhook_deliveries_old RENAME TO webhook_deliveries;
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id);
+-- +goose StatementEnd
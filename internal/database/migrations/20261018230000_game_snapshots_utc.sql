@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+UPDATE game_snapshots SET fetched_at = strftime('%Y-%m-%d %H:%M:%f', fetched_at) || '+00:00'
+WHERE fetched_at NOT LIKE '%+00:00';
+-- +goose StatementEnd
+
+-- +goose Down
+-- this migration cannot be reversed: the local offsets the snapshots were stored with are not kept,
+-- so the rows stay in UTC after a rollback
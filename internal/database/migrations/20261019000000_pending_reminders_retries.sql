@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE pending_reminders ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE pending_reminders ADD COLUMN next_attempt_at TIMESTAMP DEFAULT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE pending_reminders DROP COLUMN next_attempt_at;
+ALTER TABLE pending_reminders DROP COLUMN attempts;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS prize_checks (
+    draw_id       INTEGER NOT NULL,
+    game_type     TEXT NOT NULL REFERENCES games(type),
+    status        TEXT NOT NULL DEFAULT 'pending',
+    attempts      INTEGER NOT NULL DEFAULT 0,
+    next_check_at TIMESTAMP NOT NULL,
+    created_at    TIMESTAMP NOT NULL,
+    updated_at    TIMESTAMP NOT NULL,
+    PRIMARY KEY (draw_id, game_type)
+);
+CREATE INDEX IF NOT EXISTS idx_prize_checks_status_next_check_at ON prize_checks (status, next_check_at);
+
+CREATE TABLE IF NOT EXISTS payout_notifications (
+    draw_id       INTEGER NOT NULL,
+    game_type     TEXT NOT NULL REFERENCES games(type),
+    subscriber_id INTEGER NOT NULL REFERENCES subscribers(id),
+    sent_at       TIMESTAMP NOT NULL,
+    PRIMARY KEY (draw_id, game_type, subscriber_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS payout_notifications;
+DROP INDEX IF EXISTS idx_prize_checks_status_next_check_at;
+DROP TABLE IF EXISTS prize_checks;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+UPDATE outbox SET next_attempt_at = strftime('%Y-%m-%d %H:%M:%f', next_attempt_at) || '+00:00'
+WHERE next_attempt_at NOT LIKE '%+00:00';
+UPDATE outbox SET created_at = strftime('%Y-%m-%d %H:%M:%f', created_at) || '+00:00'
+WHERE created_at NOT LIKE '%+00:00';
+UPDATE outbox SET updated_at = strftime('%Y-%m-%d %H:%M:%f', updated_at) || '+00:00'
+WHERE updated_at NOT LIKE '%+00:00';
+-- +goose StatementEnd
+
+-- +goose Down
+-- this migration cannot be reversed: the local offsets the messages were stored with are not kept,
+-- so the rows stay in UTC after a rollback
@@ -0,0 +1,164 @@
+package matching
+
+import (
+	"errors"
+	"fmt"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/rules"
+)
+
+var (
+	ErrUnsupportedGame = errors.New("game is not supported")
+	ErrGameMismatch    = errors.New("ticket and result are for different games")
+	ErrInvalidTicket   = errors.New("invalid ticket")
+)
+
+// Tier is a prize tier of a game, rank 1 is the top prize
+type Tier struct {
+	Rank    int
+	Main    int
+	Special int
+}
+
+var romanNumerals = []string{"I", "II", "III", "IV", "V", "VI", "VII", "VIII", "IX", "X", "XI", "XII"}
+
+// Name returns the tier the way it is printed on lotto.pl, e.g. "IV"
+func (t Tier) Name() string {
+	if t.Rank >= 1 && t.Rank <= len(romanNumerals) {
+		return romanNumerals[t.Rank-1]
+	}
+	return fmt.Sprintf("%d", t.Rank)
+}
+
+// Match is the outcome of checking a ticket against a draw result
+type Match struct {
+	Ticket      models.Ticket
+	Result      models.Result
+	MainHits    int
+	SpecialHits int
+	// Tier is nil when the ticket did not win anything
+	Tier *Tier
+	// Prize is what the tier paid in this draw, nil when the ticket did not win
+	// or the prizes were not published yet. It is filled in by the caller.
+	Prize *models.Prize
+}
+
+func (m Match) Won() bool {
+	return m.Tier != nil
+}
+
+// Supported reports whether tickets of the game can be checked
+func Supported(gameType models.GameType) bool {
+	_, hasTiers := prizeTiers[gameType]
+	_, hasRules := rules.Get(gameType)
+	return hasTiers && hasRules
+}
+
+// ValidateTicket checks that the ticket numbers can be played in the game
+func ValidateTicket(ticket models.Ticket) error {
+	if !Supported(ticket.GameType) {
+		return ErrUnsupportedGame
+	}
+	gameRules, _ := rules.Get(ticket.GameType)
+
+	if err := validatePicks(ticket.Numbers, gameRules.Main); err != nil {
+		return fmt.Errorf("%w: %s numbers: %w", ErrInvalidTicket, ticket.GameType, err)
+	}
+	if gameRules.Special == nil {
+		if len(ticket.SpecialNumbers) > 0 {
+			return fmt.Errorf("%w: %s has no special numbers", ErrInvalidTicket, ticket.GameType)
+		}
+		return nil
+	}
+	if err := validatePicks(ticket.SpecialNumbers, *gameRules.Special); err != nil {
+		return fmt.Errorf("%w: %s special numbers: %w", ErrInvalidTicket, ticket.GameType, err)
+	}
+	return nil
+}
+
+func validatePicks(picked []int, pool rules.Pool) error {
+	if len(picked) < pool.MinPick || len(picked) > pool.MaxPick {
+		return fmt.Errorf("expected %d to %d numbers, got %d", pool.MinPick, pool.MaxPick, len(picked))
+	}
+	seen := make(map[int]struct{}, len(picked))
+	for _, num := range picked {
+		if num < 1 || num > pool.Size {
+			return fmt.Errorf("number %d is out of range 1-%d", num, pool.Size)
+		}
+		if _, ok := seen[num]; ok {
+			return fmt.Errorf("number %d is picked twice", num)
+		}
+		seen[num] = struct{}{}
+	}
+	return nil
+}
+
+// Check counts the hits of the ticket in the result and finds the prize tier they win
+func Check(ticket models.Ticket, result models.Result) (Match, error) {
+	if ticket.GameType != result.GameType {
+		return Match{}, ErrGameMismatch
+	}
+	if err := ValidateTicket(ticket); err != nil {
+		return Match{}, err
+	}
+
+	match := Match{
+		Ticket:      ticket,
+		Result:      result,
+		MainHits:    countHits(ticket.Numbers, result.Results),
+		SpecialHits: countHits(ticket.SpecialNumbers, result.SpecialResults),
+	}
+
+	for _, tier := range prizeTiers[ticket.GameType](len(ticket.Numbers)) {
+		if tier.Main == match.MainHits && tier.Special == match.SpecialHits {
+			match.Tier = &tier
+			break
+		}
+	}
+
+	return match, nil
+}
+
+// CheckAll checks every ticket against the result of its game.
+// Tickets without a result or that cannot be checked are skipped.
+func CheckAll(tickets []models.Ticket, results []models.Result) []Match {
+	byGame := make(map[models.GameType]models.Result, len(results))
+	for _, result := range results {
+		byGame[result.GameType] = result
+	}
+
+	matches := []Match{}
+	for _, ticket := range tickets {
+		result, ok := byGame[ticket.GameType]
+		if !ok {
+			continue
+		}
+		match, err := Check(ticket, result)
+		if err != nil {
+			continue
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+func countHits(picked, drawn []int) int {
+	drawnSet := make(map[int]struct{}, len(drawn))
+	for _, num := range drawn {
+		drawnSet[num] = struct{}{}
+	}
+
+	count := 0
+	seen := make(map[int]struct{}, len(picked))
+	for _, num := range picked {
+		if _, ok := seen[num]; ok {
+			continue
+		}
+		seen[num] = struct{}{}
+		if _, ok := drawnSet[num]; ok {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,294 @@
+package matching
+
+import (
+	"errors"
+	"testing"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/rules"
+)
+
+// seq returns the numbers from first to last
+func seq(first, last int) []int {
+	nums := make([]int, 0, last-first+1)
+	for num := first; num <= last; num++ {
+		nums = append(nums, num)
+	}
+	return nums
+}
+
+// picks returns hits numbers from the drawn 1..hits and misses numbers from missFrom on
+func picks(hits, misses, missFrom int) []int {
+	return append(seq(1, hits), seq(missFrom, missFrom+misses-1)...)
+}
+
+func TestCheckTiers(t *testing.T) {
+	lottoResult := models.Result{Results: seq(1, 6)}
+	euroJackpotResult := models.Result{Results: seq(1, 5), SpecialResults: []int{1, 2}}
+	multiMultiResult := models.Result{Results: seq(1, 20), SpecialResults: []int{7}}
+	miniLottoResult := models.Result{Results: seq(1, 5)}
+	kaskadaResult := models.Result{Results: seq(1, 12)}
+	ekstraPensjaResult := models.Result{Results: seq(1, 5), SpecialResults: []int{1}}
+
+	tests := []struct {
+		name     string
+		game     models.GameType
+		numbers  []int
+		special  []int
+		result   models.Result
+		wantMain int
+		wantSpec int
+		// wantRank is 0 when the ticket wins nothing
+		wantRank int
+	}{
+		{"Lotto 6 hits", models.GameTypeLotto, picks(6, 0, 40), nil, lottoResult, 6, 0, 1},
+		{"Lotto 5 hits", models.GameTypeLotto, picks(5, 1, 40), nil, lottoResult, 5, 0, 2},
+		{"Lotto 4 hits", models.GameTypeLotto, picks(4, 2, 40), nil, lottoResult, 4, 0, 3},
+		{"Lotto 3 hits", models.GameTypeLotto, picks(3, 3, 40), nil, lottoResult, 3, 0, 4},
+		{"Lotto 2 hits", models.GameTypeLotto, picks(2, 4, 40), nil, lottoResult, 2, 0, 0},
+		{"Lotto no hits", models.GameTypeLotto, picks(0, 6, 40), nil, lottoResult, 0, 0, 0},
+		{"LottoPlus 3 hits", models.GameTypeLottoPlus, picks(3, 3, 40), nil, lottoResult, 3, 0, 4},
+
+		{"EuroJackpot 5+2", models.GameTypeEuroJackpot, picks(5, 0, 40), []int{1, 2}, euroJackpotResult, 5, 2, 1},
+		{"EuroJackpot 5+1", models.GameTypeEuroJackpot, picks(5, 0, 40), []int{1, 12}, euroJackpotResult, 5, 1, 2},
+		{"EuroJackpot 5+0", models.GameTypeEuroJackpot, picks(5, 0, 40), []int{11, 12}, euroJackpotResult, 5, 0, 3},
+		{"EuroJackpot 4+2", models.GameTypeEuroJackpot, picks(4, 1, 40), []int{1, 2}, euroJackpotResult, 4, 2, 4},
+		{"EuroJackpot 4+1", models.GameTypeEuroJackpot, picks(4, 1, 40), []int{2, 12}, euroJackpotResult, 4, 1, 5},
+		{"EuroJackpot 3+2", models.GameTypeEuroJackpot, picks(3, 2, 40), []int{1, 2}, euroJackpotResult, 3, 2, 6},
+		{"EuroJackpot 4+0", models.GameTypeEuroJackpot, picks(4, 1, 40), []int{11, 12}, euroJackpotResult, 4, 0, 7},
+		{"EuroJackpot 2+2", models.GameTypeEuroJackpot, picks(2, 3, 40), []int{1, 2}, euroJackpotResult, 2, 2, 8},
+		{"EuroJackpot 3+1", models.GameTypeEuroJackpot, picks(3, 2, 40), []int{1, 12}, euroJackpotResult, 3, 1, 9},
+		{"EuroJackpot 3+0", models.GameTypeEuroJackpot, picks(3, 2, 40), []int{11, 12}, euroJackpotResult, 3, 0, 10},
+		{"EuroJackpot 1+2", models.GameTypeEuroJackpot, picks(1, 4, 40), []int{1, 2}, euroJackpotResult, 1, 2, 11},
+		{"EuroJackpot 2+1", models.GameTypeEuroJackpot, picks(2, 3, 40), []int{2, 12}, euroJackpotResult, 2, 1, 12},
+		{"EuroJackpot 2+0", models.GameTypeEuroJackpot, picks(2, 3, 40), []int{11, 12}, euroJackpotResult, 2, 0, 0},
+		{"EuroJackpot 1+1", models.GameTypeEuroJackpot, picks(1, 4, 40), []int{1, 12}, euroJackpotResult, 1, 1, 0},
+		{"EuroJackpot 0+2", models.GameTypeEuroJackpot, picks(0, 5, 40), []int{1, 2}, euroJackpotResult, 0, 2, 0},
+
+		{"MultiMulti 10 of 10", models.GameTypeMultiMulti, picks(10, 0, 70), nil, multiMultiResult, 10, 0, 1},
+		{"MultiMulti 5 of 10", models.GameTypeMultiMulti, picks(5, 5, 70), nil, multiMultiResult, 5, 0, 6},
+		{"MultiMulti 4 of 10", models.GameTypeMultiMulti, picks(4, 6, 70), nil, multiMultiResult, 4, 0, 0},
+		{"MultiMulti 4 of 9", models.GameTypeMultiMulti, picks(4, 5, 70), nil, multiMultiResult, 4, 0, 6},
+		{"MultiMulti 3 of 7", models.GameTypeMultiMulti, picks(3, 4, 70), nil, multiMultiResult, 3, 0, 5},
+		{"MultiMulti 2 of 4", models.GameTypeMultiMulti, picks(2, 2, 70), nil, multiMultiResult, 2, 0, 3},
+		{"MultiMulti 1 of 3", models.GameTypeMultiMulti, picks(1, 2, 70), nil, multiMultiResult, 1, 0, 0},
+		{"MultiMulti 2 of 2", models.GameTypeMultiMulti, picks(2, 0, 70), nil, multiMultiResult, 2, 0, 1},
+		{"MultiMulti 1 of 1", models.GameTypeMultiMulti, picks(1, 0, 70), nil, multiMultiResult, 1, 0, 1},
+		{"MultiMulti 0 of 1", models.GameTypeMultiMulti, picks(0, 1, 70), nil, multiMultiResult, 0, 0, 0},
+
+		{"MiniLotto 5 hits", models.GameTypeMiniLotto, picks(5, 0, 30), nil, miniLottoResult, 5, 0, 1},
+		{"MiniLotto 3 hits", models.GameTypeMiniLotto, picks(3, 2, 30), nil, miniLottoResult, 3, 0, 3},
+		{"MiniLotto 2 hits", models.GameTypeMiniLotto, picks(2, 3, 30), nil, miniLottoResult, 2, 0, 0},
+
+		{"Kaskada 12 hits", models.GameTypeKaskada, picks(12, 0, 13), nil, kaskadaResult, 12, 0, 1},
+		{"Kaskada 11 hits", models.GameTypeKaskada, picks(11, 1, 13), nil, kaskadaResult, 11, 0, 2},
+		{"Kaskada 8 hits", models.GameTypeKaskada, picks(8, 4, 13), nil, kaskadaResult, 8, 0, 5},
+		{"Kaskada 7 hits", models.GameTypeKaskada, picks(7, 5, 13), nil, kaskadaResult, 7, 0, 0},
+
+		{"EkstraPensja 5+1", models.GameTypeEkstraPensja, picks(5, 0, 30), []int{1}, ekstraPensjaResult, 5, 1, 1},
+		{"EkstraPensja 5+0", models.GameTypeEkstraPensja, picks(5, 0, 30), []int{4}, ekstraPensjaResult, 5, 0, 2},
+		{"EkstraPensja 4+1", models.GameTypeEkstraPensja, picks(4, 1, 30), []int{1}, ekstraPensjaResult, 4, 1, 3},
+		{"EkstraPensja 3+0", models.GameTypeEkstraPensja, picks(3, 2, 30), []int{4}, ekstraPensjaResult, 3, 0, 6},
+		{"EkstraPensja 2+1", models.GameTypeEkstraPensja, picks(2, 3, 30), []int{1}, ekstraPensjaResult, 2, 1, 7},
+		{"EkstraPensja 1+1", models.GameTypeEkstraPensja, picks(1, 4, 30), []int{1}, ekstraPensjaResult, 1, 1, 8},
+		{"EkstraPensja 2+0", models.GameTypeEkstraPensja, picks(2, 3, 30), []int{4}, ekstraPensjaResult, 2, 0, 0},
+		{"EkstraPensja 0+1", models.GameTypeEkstraPensja, picks(0, 5, 30), []int{1}, ekstraPensjaResult, 0, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticket := models.Ticket{GameType: tt.game, Numbers: tt.numbers, SpecialNumbers: tt.special}
+			result := tt.result
+			result.GameType = tt.game
+
+			match, err := Check(ticket, result)
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if match.MainHits != tt.wantMain || match.SpecialHits != tt.wantSpec {
+				t.Errorf("hits = %d+%d, want %d+%d", match.MainHits, match.SpecialHits, tt.wantMain, tt.wantSpec)
+			}
+			switch {
+			case tt.wantRank == 0 && match.Won():
+				t.Errorf("tier = %s, want no prize", match.Tier.Name())
+			case tt.wantRank != 0 && !match.Won():
+				t.Errorf("no prize, want tier %d", tt.wantRank)
+			case tt.wantRank != 0 && match.Tier.Rank != tt.wantRank:
+				t.Errorf("tier = %d, want %d", match.Tier.Rank, tt.wantRank)
+			}
+		})
+	}
+}
+
+func TestCheckErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		ticket  models.Ticket
+		result  models.Result
+		wantErr error
+	}{
+		{
+			name:    "different games",
+			ticket:  models.Ticket{GameType: models.GameTypeLotto, Numbers: seq(1, 6)},
+			result:  models.Result{GameType: models.GameTypeMiniLotto, Results: seq(1, 5)},
+			wantErr: ErrGameMismatch,
+		},
+		{
+			name:    "unsupported game",
+			ticket:  models.Ticket{GameType: "Keno", Numbers: seq(1, 6)},
+			result:  models.Result{GameType: "Keno", Results: seq(1, 6)},
+			wantErr: ErrUnsupportedGame,
+		},
+		{
+			name:    "invalid ticket",
+			ticket:  models.Ticket{GameType: models.GameTypeLotto, Numbers: seq(1, 5)},
+			result:  models.Result{GameType: models.GameTypeLotto, Results: seq(1, 6)},
+			wantErr: ErrInvalidTicket,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Check(tt.ticket, tt.result); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Check() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePicks(t *testing.T) {
+	lotto := rules.Pool{Size: 49, Drawn: 6, MinPick: 6, MaxPick: 6}
+	multiMulti := rules.Pool{Size: 80, Drawn: 20, MinPick: 1, MaxPick: 10}
+
+	tests := []struct {
+		name    string
+		picked  []int
+		pool    rules.Pool
+		wantErr bool
+	}{
+		{"exact count", seq(1, 6), lotto, false},
+		{"highest number", []int{44, 45, 46, 47, 48, 49}, lotto, false},
+		{"too few", seq(1, 5), lotto, true},
+		{"too many", seq(1, 7), lotto, true},
+		{"none", nil, lotto, true},
+		{"zero", []int{0, 1, 2, 3, 4, 5}, lotto, true},
+		{"negative", []int{-1, 1, 2, 3, 4, 5}, lotto, true},
+		{"above the pool", []int{1, 2, 3, 4, 5, 50}, lotto, true},
+		{"picked twice", []int{1, 2, 3, 4, 5, 5}, lotto, true},
+		{"range minimum", []int{80}, multiMulti, false},
+		{"range maximum", seq(71, 80), multiMulti, false},
+		{"below range", nil, multiMulti, true},
+		{"above range", seq(1, 11), multiMulti, true},
+		{"above the range pool", []int{1, 81}, multiMulti, true},
+		{"picked twice in range", []int{7, 7}, multiMulti, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePicks(tt.picked, tt.pool)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validatePicks(%v) error = %v, want error %t", tt.picked, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTicket(t *testing.T) {
+	tests := []struct {
+		name    string
+		ticket  models.Ticket
+		wantErr error
+	}{
+		{
+			name:   "Lotto",
+			ticket: models.Ticket{GameType: models.GameTypeLotto, Numbers: seq(1, 6)},
+		},
+		{
+			name:    "Lotto with special numbers",
+			ticket:  models.Ticket{GameType: models.GameTypeLotto, Numbers: seq(1, 6), SpecialNumbers: []int{1}},
+			wantErr: ErrInvalidTicket,
+		},
+		{
+			name:   "EuroJackpot",
+			ticket: models.Ticket{GameType: models.GameTypeEuroJackpot, Numbers: seq(1, 5), SpecialNumbers: []int{1, 12}},
+		},
+		{
+			name:    "EuroJackpot without special numbers",
+			ticket:  models.Ticket{GameType: models.GameTypeEuroJackpot, Numbers: seq(1, 5)},
+			wantErr: ErrInvalidTicket,
+		},
+		{
+			name:    "EuroJackpot special number out of range",
+			ticket:  models.Ticket{GameType: models.GameTypeEuroJackpot, Numbers: seq(1, 5), SpecialNumbers: []int{1, 13}},
+			wantErr: ErrInvalidTicket,
+		},
+		{
+			name:    "MultiMulti with special numbers",
+			ticket:  models.Ticket{GameType: models.GameTypeMultiMulti, Numbers: seq(1, 5), SpecialNumbers: []int{1}},
+			wantErr: ErrInvalidTicket,
+		},
+		{
+			name:    "EkstraPensja special number out of range",
+			ticket:  models.Ticket{GameType: models.GameTypeEkstraPensja, Numbers: seq(1, 5), SpecialNumbers: []int{5}},
+			wantErr: ErrInvalidTicket,
+		},
+		{
+			name:    "unsupported game",
+			ticket:  models.Ticket{GameType: "Keno", Numbers: seq(1, 6)},
+			wantErr: ErrUnsupportedGame,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateTicket(tt.ticket); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateTicket() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckAll(t *testing.T) {
+	tickets := []models.Ticket{
+		{ID: 1, GameType: models.GameTypeLotto, Numbers: seq(1, 6)},
+		// no result for the game
+		{ID: 2, GameType: models.GameTypeMiniLotto, Numbers: seq(1, 5)},
+		// cannot be played
+		{ID: 3, GameType: models.GameTypeLotto, Numbers: seq(1, 3)},
+		{ID: 4, GameType: models.GameTypeLottoPlus, Numbers: picks(0, 6, 40)},
+	}
+	results := []models.Result{
+		{GameType: models.GameTypeLotto, Results: seq(1, 6)},
+		{GameType: models.GameTypeLottoPlus, Results: seq(1, 6)},
+	}
+
+	matches := CheckAll(tickets, results)
+	if len(matches) != 2 {
+		t.Fatalf("matches = %d, want 2", len(matches))
+	}
+	if matches[0].Ticket.ID != 1 || !matches[0].Won() || matches[0].Tier.Rank != 1 {
+		t.Errorf("first match = ticket %d tier %v, want ticket 1 tier I", matches[0].Ticket.ID, matches[0].Tier)
+	}
+	if matches[1].Ticket.ID != 4 || matches[1].Won() {
+		t.Errorf("second match = ticket %d tier %v, want ticket 4 without prize", matches[1].Ticket.ID, matches[1].Tier)
+	}
+}
+
+func TestTierName(t *testing.T) {
+	tests := []struct {
+		rank int
+		want string
+	}{
+		{1, "I"},
+		{4, "IV"},
+		{9, "IX"},
+		{12, "XII"},
+		{13, "13"},
+	}
+
+	for _, tt := range tests {
+		if got := (Tier{Rank: tt.rank}).Name(); got != tt.want {
+			t.Errorf("Tier{Rank: %d}.Name() = %q, want %q", tt.rank, got, tt.want)
+		}
+	}
+}
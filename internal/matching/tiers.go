@@ -0,0 +1,62 @@
+package matching
+
+import "lotto-notifications/internal/models"
+
+// tiersFunc returns the winning tiers for a ticket with the given count of picked numbers,
+// ordered from the top prize
+type tiersFunc func(picked int) []Tier
+
+func fixedTiers(tiers ...Tier) tiersFunc {
+	for idx := range tiers {
+		tiers[idx].Rank = idx + 1
+	}
+	return func(int) []Tier { return tiers }
+}
+
+func hits(main int) Tier {
+	return Tier{Main: main}
+}
+
+func hitsPlus(main, special int) Tier {
+	return Tier{Main: main, Special: special}
+}
+
+var lottoTiers = fixedTiers(hits(6), hits(5), hits(4), hits(3))
+
+// multiMultiTiers lists the winning hit counts for each count of picked numbers
+var multiMultiTiers = map[int][]int{
+	10: {10, 9, 8, 7, 6, 5},
+	9:  {9, 8, 7, 6, 5, 4},
+	8:  {8, 7, 6, 5, 4},
+	7:  {7, 6, 5, 4, 3},
+	6:  {6, 5, 4, 3},
+	5:  {5, 4, 3},
+	4:  {4, 3, 2},
+	3:  {3, 2},
+	2:  {2},
+	1:  {1},
+}
+
+var prizeTiers = map[models.GameType]tiersFunc{
+	models.GameTypeLotto:     lottoTiers,
+	models.GameTypeLottoPlus: lottoTiers,
+	models.GameTypeEuroJackpot: fixedTiers(
+		hitsPlus(5, 2), hitsPlus(5, 1), hitsPlus(5, 0),
+		hitsPlus(4, 2), hitsPlus(4, 1), hitsPlus(3, 2),
+		hitsPlus(4, 0), hitsPlus(2, 2), hitsPlus(3, 1),
+		hitsPlus(3, 0), hitsPlus(1, 2), hitsPlus(2, 1),
+	),
+	models.GameTypeMiniLotto: fixedTiers(hits(5), hits(4), hits(3)),
+	models.GameTypeMultiMulti: func(picked int) []Tier {
+		tiers := make([]Tier, len(multiMultiTiers[picked]))
+		for idx, main := range multiMultiTiers[picked] {
+			tiers[idx] = Tier{Rank: idx + 1, Main: main}
+		}
+		return tiers
+	},
+	models.GameTypeKaskada: fixedTiers(hits(12), hits(11), hits(10), hits(9), hits(8)),
+	models.GameTypeEkstraPensja: fixedTiers(
+		hitsPlus(5, 1), hitsPlus(5, 0), hitsPlus(4, 1), hitsPlus(4, 0),
+		hitsPlus(3, 1), hitsPlus(3, 0), hitsPlus(2, 1), hitsPlus(1, 1),
+	),
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// BackfillCheckpoint is the progress of walking a game's history between From and To.
+// NextPage is the first page that was not stored yet.
+type BackfillCheckpoint struct {
+	GameType    GameType   `db:"game_type"`
+	From        time.Time  `db:"from_date"`
+	To          time.Time  `db:"to_date"`
+	NextPage    int        `db:"next_page"`
+	Inserted    int        `db:"inserted"`
+	CompletedAt *time.Time `db:"completed_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+}
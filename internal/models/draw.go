@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Draw is a draw as returned by the API with its draw-level flags. A draw holds several
+// result sets when add-on games are drawn with it, e.g. Lotto with LottoPlus and SuperSzansa;
+// every result set is stored as its own Result pointing back at the draw.
+type Draw struct {
+	DrawID               uint      `db:"draw_id"`
+	GameType             GameType  `db:"game_type"`
+	DrawDate             time.Time `db:"draw_date"`
+	MultiplierValue      uint      `db:"multiplier_value"`
+	ShowSpecialResults   bool      `db:"show_special_results"`
+	IsNewEuroJackpotDraw bool      `db:"is_new_eurojackpot_draw"`
+	CreatedAt            time.Time `db:"created_at"`
+
+	Results []Result `db:"-"`
+}
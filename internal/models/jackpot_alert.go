@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// JackpotAlert asks for a one-time message when a game's jackpot reaches Threshold.
+// NotifiedAt and NotifiedValue are set once the alert fired for the current jackpot run
+// and cleared when the jackpot is won.
+type JackpotAlert struct {
+	ID            int64      `db:"id"`
+	SubscriberID  int64      `db:"subscriber_id"`
+	GameType      GameType   `db:"game_type"`
+	Threshold     float64    `db:"threshold"`
+	NotifiedAt    *time.Time `db:"notified_at"`
+	NotifiedValue *float64   `db:"notified_value"`
+	CreatedAt     time.Time  `db:"created_at"`
+}
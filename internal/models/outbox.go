@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+type OutboxStatus string
+
+const (
+	OutboxStatusPending OutboxStatus = "pending"
+	OutboxStatusSent    OutboxStatus = "sent"
+	// OutboxStatusDead is set once a message ran out of delivery attempts
+	OutboxStatusDead OutboxStatus = "dead"
+	// OutboxStatusExpanded is set once a message was split into one message per target
+	OutboxStatusExpanded OutboxStatus = "expanded"
+)
+
+// OutboxMessage is a notification about newly saved results waiting to be delivered to one channel.
+// Payload holds the results encoded as JSON. A message for a channel with many recipients is expanded
+// into one message per recipient, those point to it with ParentID and name the recipient in Target.
+type OutboxMessage struct {
+	ID            int64        `db:"id"`
+	Channel       string       `db:"channel"`
+	ParentID      *int64       `db:"parent_id"`
+	Target        *string      `db:"target"`
+	GameType      GameType     `db:"game_type"`
+	Payload       string       `db:"payload"`
+	Status        OutboxStatus `db:"status"`
+	Attempts      int          `db:"attempts"`
+	NextAttemptAt time.Time    `db:"next_attempt_at"`
+	LastError     *string      `db:"last_error"`
+	CreatedAt     time.Time    `db:"created_at"`
+	UpdatedAt     time.Time    `db:"updated_at"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Prize is what a prize tier of a draw paid out, tier 1 is the top prize.
+// Amount is the prize per winning ticket.
+type Prize struct {
+	DrawID    uint      `db:"draw_id"`
+	GameType  GameType  `db:"game_type"`
+	Tier      int       `db:"tier"`
+	Winners   int       `db:"winners"`
+	Amount    float64   `db:"amount"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type PrizeCheckStatus string
+
+const (
+	PrizeCheckStatusPending PrizeCheckStatus = "pending"
+	// PrizeCheckStatusDone is set once the prizes were stored and the winners told
+	PrizeCheckStatusDone PrizeCheckStatus = "done"
+	// PrizeCheckStatusExpired is set when the prizes were not published in time
+	PrizeCheckStatusExpired PrizeCheckStatus = "expired"
+)
+
+// PrizeCheck is a saved result whose prizes are polled for until they are published.
+// Its times are stored in UTC.
+type PrizeCheck struct {
+	DrawID      uint             `db:"draw_id"`
+	GameType    GameType         `db:"game_type"`
+	Status      PrizeCheckStatus `db:"status"`
+	Attempts    int              `db:"attempts"`
+	NextCheckAt time.Time        `db:"next_check_at"`
+	CreatedAt   time.Time        `db:"created_at"`
+	UpdatedAt   time.Time        `db:"updated_at"`
+}
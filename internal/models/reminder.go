@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Reminder asks for a message OffsetMinutes before every draw of a game
+type Reminder struct {
+	ID            int64     `db:"id"`
+	SubscriberID  int64     `db:"subscriber_id"`
+	GameType      GameType  `db:"game_type"`
+	OffsetMinutes int       `db:"offset_minutes"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+func (r Reminder) Offset() time.Duration {
+	return time.Duration(r.OffsetMinutes) * time.Minute
+}
+
+type PendingReminderStatus string
+
+const (
+	PendingReminderStatusPending PendingReminderStatus = "pending"
+	PendingReminderStatusSent    PendingReminderStatus = "sent"
+	// PendingReminderStatusSkipped is set for reminders that were due while the worker was down
+	PendingReminderStatusSkipped PendingReminderStatus = "skipped"
+)
+
+// PendingReminder is a reminder scheduled for one draw, it carries the subscriber and game of its reminder
+type PendingReminder struct {
+	ID           int64                 `db:"id"`
+	ReminderID   int64                 `db:"reminder_id"`
+	SubscriberID int64                 `db:"subscriber_id"`
+	GameType     GameType              `db:"game_type"`
+	DrawDate     time.Time             `db:"draw_date"`
+	FireAt       time.Time             `db:"fire_at"`
+	Status       PendingReminderStatus `db:"status"`
+	// Attempts counts the failed sends, NextAttemptAt is when a failed reminder is retried
+	Attempts      int        `db:"attempts"`
+	NextAttemptAt *time.Time `db:"next_attempt_at"`
+	UpdatedAt     time.Time  `db:"updated_at"`
+}
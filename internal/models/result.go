@@ -17,6 +17,18 @@ type Result struct {
 	Results        IntSlice  `db:"results"`
 	SpecialResults IntSlice  `db:"special_results"`
 	CreatedAt      time.Time `db:"created_at"`
+	// ParentDrawID and ParentGameType identify the draw the result came in,
+	// they are nil for results stored before draws were kept
+	ParentDrawID   *uint     `db:"parent_draw_id"`
+	ParentGameType *GameType `db:"parent_game_type"`
+}
+
+// QuarantinedResult is a result returned by the API that failed validation
+// and was kept aside instead of being saved and notified
+type QuarantinedResult struct {
+	ID int64 `db:"id"`
+	Result
+	Reason string `db:"reason"`
 }
 
 func (s IntSlice) Value() (driver.Value, error) {
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// GameSnapshot is the game info as it was fetched at FetchedAt
+type GameSnapshot struct {
+	ID                int64      `db:"id"`
+	GameType          GameType   `db:"game_type"`
+	NextDrawDate      *time.Time `db:"next_draw_date"`
+	ClosestPrizeValue *float64   `db:"closest_prize_value"`
+	ClosestPrizePool  *string    `db:"closest_prize_pool"`
+	CouponPrice       *string    `db:"coupon_price"`
+	FetchedAt         time.Time  `db:"fetched_at"`
+}
+
+// JackpotHit is a jackpot that dropped between two snapshots, i.e. it was won in the draw held at DrawDate
+type JackpotHit struct {
+	GameType GameType
+	DrawDate *time.Time
+	// Jackpot is the value before the draw and NextJackpot the one the next run started with
+	Jackpot     float64
+	NextJackpot float64
+	DetectedAt  time.Time
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+type ChannelType string
+
+const (
+	ChannelTypeEmail    ChannelType = "email"
+	ChannelTypeTelegram ChannelType = "telegram"
+	// ChannelTypeNtfy addresses are topic URLs, e.g. https://ntfy.sh/my-topic
+	ChannelTypeNtfy ChannelType = "ntfy"
+	// ChannelTypeGotify addresses are server URLs, the token is the application token
+	ChannelTypeGotify ChannelType = "gotify"
+)
+
+type Subscriber struct {
+	ID        int64     `db:"id"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// SubscriberChannel is a way of reaching a subscriber, e.g. an email address.
+// Token holds the credentials some channels need to deliver on the subscriber's behalf.
+type SubscriberChannel struct {
+	ID           int64       `db:"id"`
+	SubscriberID int64       `db:"subscriber_id"`
+	Type         ChannelType `db:"type"`
+	Address      string      `db:"address"`
+	Token        *string     `db:"token"`
+	CreatedAt    time.Time   `db:"created_at"`
+}
+
+// Subscription opts a subscriber in to the results of a game.
+// Subscribers without any subscriptions receive the results of every game.
+type Subscription struct {
+	SubscriberID int64     `db:"subscriber_id"`
+	GameType     GameType  `db:"game_type"`
+	CreatedAt    time.Time `db:"created_at"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Ticket is a set of numbers a subscriber plays in a game
+type Ticket struct {
+	ID             int64     `db:"id"`
+	SubscriberID   int64     `db:"subscriber_id"`
+	GameType       GameType  `db:"game_type"`
+	Numbers        IntSlice  `db:"numbers"`
+	SpecialNumbers IntSlice  `db:"special_numbers"`
+	CreatedAt      time.Time `db:"created_at"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Webhook is an HTTP endpoint that receives every newly saved draw,
+// signed with its own secret
+type Webhook struct {
+	ID        int64     `db:"id"`
+	URL       string    `db:"url"`
+	Secret    string    `db:"secret"`
+	Active    bool      `db:"active"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// WebhookDelivery records a single attempt to deliver to a webhook, retries of a delivery share its DeliveryID.
+// StatusCode is nil when no response was received.
+type WebhookDelivery struct {
+	ID         int64     `db:"id"`
+	WebhookID  int64     `db:"webhook_id"`
+	DeliveryID string    `db:"delivery_id"`
+	StatusCode *int      `db:"status_code"`
+	Error      *string   `db:"error"`
+	DurationMS int64     `db:"duration_ms"`
+	CreatedAt  time.Time `db:"created_at"`
+}
@@ -0,0 +1,87 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/notifier"
+)
+
+type field struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+type embed struct {
+	Title     string  `json:"title"`
+	Color     int     `json:"color"`
+	Timestamp string  `json:"timestamp,omitempty"`
+	Fields    []field `json:"fields"`
+}
+
+type message struct {
+	Embeds []embed `json:"embeds"`
+}
+
+type discordNotifier struct {
+	httpClient *http.Client
+	webhookURL string
+}
+
+// New returns a channel that posts the results to a Discord webhook
+func New(webhookURL string) notifier.Notifier {
+	return &discordNotifier{
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		webhookURL: webhookURL,
+	}
+}
+
+func (n *discordNotifier) Name() string {
+	return "discord"
+}
+
+func (n *discordNotifier) Notify(ctx context.Context, game models.Game, results []models.Result) error {
+	return notifier.PostJSON(ctx, n.httpClient, n.webhookURL, nil, newMessage(game, results))
+}
+
+func newMessage(game models.Game, results []models.Result) message {
+	e := embed{
+		Title: fmt.Sprintf("%s draw results", game.GameType),
+		Color: notifier.GameColor(game.GameType),
+	}
+	if len(results) > 0 {
+		e.Timestamp = results[0].DrawDate.UTC().Format(time.RFC3339)
+	}
+
+	for _, result := range results {
+		value := formatNumbers(result.Results)
+		if len(result.SpecialResults) > 0 {
+			value += "\n+ " + formatNumbers(result.SpecialResults)
+		}
+		e.Fields = append(e.Fields, field{
+			Name:  fmt.Sprintf("%s, draw %d, %s", result.GameType, result.DrawID, notifier.FormatDate(result.DrawDate)),
+			Value: value,
+		})
+	}
+
+	if game.NextDrawDate != nil {
+		e.Fields = append(e.Fields, field{Name: "Next draw", Value: notifier.FormatDate(*game.NextDrawDate), Inline: true})
+	}
+	if game.ClosestPrizeValue != nil {
+		e.Fields = append(e.Fields, field{Name: "Jackpot", Value: notifier.FormatPLN(*game.ClosestPrizeValue), Inline: true})
+	}
+
+	return message{Embeds: []embed{e}}
+}
+
+func formatNumbers(numbers []int) string {
+	strs := strings.Split(notifier.FormatNumbers(numbers), ", ")
+	return "**" + strings.Join(strs, "** · **") + "**"
+}
@@ -0,0 +1,140 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"lotto-notifications/internal/models"
+)
+
+var drawDate = time.Date(2026, time.October, 17, 20, 0, 0, 0, time.UTC)
+
+func lottoResults() (models.Game, []models.Result) {
+	nextDrawDate := time.Date(2026, time.October, 20, 20, 0, 0, 0, time.UTC)
+	jackpot := 15_000_000.0
+	game := models.Game{GameType: models.GameTypeLotto, NextDrawDate: &nextDrawDate, ClosestPrizeValue: &jackpot}
+	results := []models.Result{
+		{DrawID: 7000, GameType: models.GameTypeLotto, DrawDate: drawDate, Results: models.IntSlice{3, 12, 27, 31, 40, 49}},
+		{DrawID: 7000, GameType: models.GameTypeLottoPlus, DrawDate: drawDate, Results: models.IntSlice{1, 2, 3, 4, 5, 6}},
+	}
+	return game, results
+}
+
+func TestNewMessage(t *testing.T) {
+	game, results := lottoResults()
+	payload, err := json.Marshal(newMessage(game, results))
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var got message
+	if err := json.Unmarshal(payload, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if len(got.Embeds) != 1 {
+		t.Fatalf("got %d embeds, want 1", len(got.Embeds))
+	}
+	e := got.Embeds[0]
+	if e.Title != "Lotto draw results" {
+		t.Errorf("title = %q, want %q", e.Title, "Lotto draw results")
+	}
+	if e.Color != 0xF6B800 {
+		t.Errorf("color = %#x, want the Lotto colour 0xf6b800", e.Color)
+	}
+	if e.Timestamp != "2026-10-17T20:00:00Z" {
+		t.Errorf("timestamp = %q, want the draw date in UTC", e.Timestamp)
+	}
+
+	want := []field{
+		{Name: "Lotto, draw 7000, 2026-10-17 22:00", Value: "**3** · **12** · **27** · **31** · **40** · **49**"},
+		{Name: "LottoPlus, draw 7000, 2026-10-17 22:00", Value: "**1** · **2** · **3** · **4** · **5** · **6**"},
+		{Name: "Next draw", Value: "2026-10-20 22:00", Inline: true},
+		{Name: "Jackpot", Value: "15 000 000 PLN", Inline: true},
+	}
+	if !slices.Equal(e.Fields, want) {
+		t.Errorf("fields = %+v\nwant %+v", e.Fields, want)
+	}
+}
+
+func TestNewMessageSpecialNumbers(t *testing.T) {
+	game := models.Game{GameType: models.GameTypeEuroJackpot}
+	results := []models.Result{{
+		DrawID:         900,
+		GameType:       models.GameTypeEuroJackpot,
+		DrawDate:       drawDate,
+		Results:        models.IntSlice{5, 11, 23, 38, 44},
+		SpecialResults: models.IntSlice{2, 9},
+	}}
+
+	e := newMessage(game, results).Embeds[0]
+	if e.Color != 0xF28C00 {
+		t.Errorf("color = %#x, want the EuroJackpot colour 0xf28c00", e.Color)
+	}
+	want := []field{{
+		Name:  "EuroJackpot, draw 900, 2026-10-17 22:00",
+		Value: "**5** · **11** · **23** · **38** · **44**\n+ **2** · **9**",
+	}}
+	if !slices.Equal(e.Fields, want) {
+		t.Errorf("fields = %+v\nwant %+v", e.Fields, want)
+	}
+}
+
+func TestNotifyRetriesRateLimited(t *testing.T) {
+	var mu sync.Mutex
+	var bodies [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, body)
+		first := len(bodies) == 1
+		mu.Unlock()
+
+		if first {
+			// Discord answers with fractional seconds
+			w.Header().Set("Retry-After", "0.01")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	game, results := lottoResults()
+	if err := New(server.URL).Notify(context.Background(), game, results); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(bodies) != 2 {
+		t.Fatalf("received %d requests, want 2", len(bodies))
+	}
+	if string(bodies[0]) != string(bodies[1]) {
+		t.Errorf("retried body %s differs from %s", bodies[1], bodies[0])
+	}
+	var msg message
+	if err := json.Unmarshal(bodies[1], &msg); err != nil || len(msg.Embeds) != 1 {
+		t.Errorf("posted %s, %v, want one embed", bodies[1], err)
+	}
+}
+
+func TestNotifyFailsOnClientError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "Unknown Webhook"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	game, results := lottoResults()
+	err := New(server.URL).Notify(context.Background(), game, results)
+	if err == nil {
+		t.Fatal("Notify() succeeded, want the 404 error")
+	}
+}
@@ -0,0 +1,181 @@
+package email
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"net/mail"
+	"strings"
+	texttemplate "text/template"
+
+	"lotto-notifications/internal/matching"
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/notifier"
+	"lotto-notifications/internal/repository"
+)
+
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+var templateFuncs = map[string]any{
+	"numbers": notifier.FormatNumbers,
+	"date":    notifier.FormatDate,
+	"pln":     notifier.FormatPLN,
+}
+
+type TLSMode string
+
+const (
+	TLSNone     TLSMode = "none"
+	TLSStartTLS TLSMode = "starttls"
+	TLSImplicit TLSMode = "tls"
+)
+
+type Config struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	TLS      TLSMode
+}
+
+// templateData is what the results templates are rendered with
+type templateData struct {
+	Game    models.Game
+	Results []models.Result
+	Matches []matching.Match
+}
+
+type emailNotifier struct {
+	cfg  Config
+	from *mail.Address
+	repo repository.Repository
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// New returns a channel that emails the results to the subscribers with an email channel
+func New(cfg Config, repo repository.Repository) (notifier.Notifier, error) {
+	switch cfg.TLS {
+	case TLSNone, TLSStartTLS, TLSImplicit:
+	default:
+		return nil, fmt.Errorf("unknown SMTP TLS mode %q", cfg.TLS)
+	}
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP sender address: %w", err)
+	}
+
+	text, err := texttemplate.New("results.txt.tmpl").Funcs(templateFuncs).ParseFS(templates, "templates/results.txt.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse text template: %w", err)
+	}
+	html, err := htmltemplate.New("results.html.tmpl").Funcs(templateFuncs).ParseFS(templates, "templates/results.html.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse html template: %w", err)
+	}
+
+	return &emailNotifier{
+		cfg:  cfg,
+		from: from,
+		repo: repo,
+		text: text,
+		html: html,
+	}, nil
+}
+
+func (n *emailNotifier) Name() string {
+	return string(models.ChannelTypeEmail)
+}
+
+func (n *emailNotifier) Notify(ctx context.Context, game models.Game, results []models.Result) error {
+	recipients, err := notifier.Recipients(ctx, n.repo, models.ChannelTypeEmail, game, results)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, recipient := range recipients {
+		if err := n.notifyRecipient(ctx, recipient, game, results); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *emailNotifier) Targets(ctx context.Context, game models.Game, results []models.Result) ([]string, error) {
+	return notifier.RecipientTargets(ctx, n.repo, models.ChannelTypeEmail, game, results)
+}
+
+func (n *emailNotifier) NotifyTarget(
+	ctx context.Context, target notifier.Target, game models.Game, results []models.Result,
+) error {
+	recipient, ok, err := notifier.TargetRecipient(ctx, n.repo, models.ChannelTypeEmail, target.Key, game, results)
+	if err != nil || !ok {
+		return err
+	}
+	return n.notifyRecipient(ctx, recipient, game, results)
+}
+
+func (n *emailNotifier) notifyRecipient(
+	ctx context.Context, recipient notifier.Recipient, game models.Game, results []models.Result,
+) error {
+	address := recipient.Channel.Address
+	data := templateData{
+		Game:    game,
+		Results: results,
+		Matches: recipient.Matches,
+	}
+	msg, err := n.render(address, data)
+	if err != nil {
+		return fmt.Errorf("failed to render email to %s: %w", address, err)
+	}
+	if err := n.send(ctx, address, msg); err != nil {
+		return fmt.Errorf("failed to send email to %s: %w", address, err)
+	}
+	return nil
+}
+
+func (n *emailNotifier) ChannelType() models.ChannelType {
+	return models.ChannelTypeEmail
+}
+
+func (n *emailNotifier) Send(ctx context.Context, channel models.SubscriberChannel, msg notifier.Message) error {
+	html := "<p>" + strings.ReplaceAll(htmltemplate.HTMLEscapeString(msg.Text), "\n", "<br>") + "</p>"
+	body, err := buildMessage(n.from.String(), channel.Address, msg.Title, msg.Text, html)
+	if err != nil {
+		return err
+	}
+	if err := n.send(ctx, channel.Address, body); err != nil {
+		return fmt.Errorf("failed to send email to %s: %w", channel.Address, err)
+	}
+	return nil
+}
+
+func (n *emailNotifier) render(to string, data templateData) ([]byte, error) {
+	var text, html strings.Builder
+	if err := n.text.Execute(&text, data); err != nil {
+		return nil, fmt.Errorf("failed to render text template: %w", err)
+	}
+	if err := n.html.Execute(&html, data); err != nil {
+		return nil, fmt.Errorf("failed to render html template: %w", err)
+	}
+
+	return buildMessage(n.from.String(), to, subject(data), text.String(), html.String())
+}
+
+func subject(data templateData) string {
+	subject := fmt.Sprintf("%s results", data.Game.GameType)
+	if len(data.Results) > 0 {
+		subject += " " + notifier.FormatDate(data.Results[0].DrawDate)
+	}
+	for _, match := range data.Matches {
+		if match.Won() {
+			return subject + " - you won!"
+		}
+	}
+	return subject
+}
@@ -0,0 +1,394 @@
+package email
+
+import (
+	"context"
+	"flag"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	texttemplate "text/template"
+	"time"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/notifier"
+	"lotto-notifications/internal/repository"
+	"lotto-notifications/internal/repository/repositorytest"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// smtpMessage is a message received by the fake SMTP server
+type smtpMessage struct {
+	From string
+	To   []string
+	Data string
+}
+
+// smtpServer is an in-process SMTP server that accepts every message
+// except the ones addressed to rejected recipients
+type smtpServer struct {
+	listener net.Listener
+	rejected map[string]bool
+
+	mu       sync.Mutex
+	messages []smtpMessage
+}
+
+func newSMTPServer(t *testing.T, rejected ...string) *smtpServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	s := &smtpServer{listener: listener, rejected: map[string]bool{}}
+	for _, address := range rejected {
+		s.rejected[address] = true
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpServer) received() []smtpMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]smtpMessage(nil), s.messages...)
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+	defer conn.Close()
+	tc := textproto.NewConn(conn)
+	tc.PrintfLine("220 localhost fake SMTP")
+
+	var msg smtpMessage
+	for {
+		line, err := tc.ReadLine()
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			tc.PrintfLine("250 localhost")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			msg = smtpMessage{From: address(line[len("MAIL FROM:"):])}
+			tc.PrintfLine("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			to := address(line[len("RCPT TO:"):])
+			if s.rejected[to] {
+				tc.PrintfLine("550 no such user")
+				continue
+			}
+			msg.To = append(msg.To, to)
+			tc.PrintfLine("250 OK")
+		case command == "DATA":
+			tc.PrintfLine("354 go ahead")
+			data, err := io.ReadAll(tc.DotReader())
+			if err != nil {
+				return
+			}
+			msg.Data = string(data)
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+			tc.PrintfLine("250 OK")
+		case command == "QUIT":
+			tc.PrintfLine("221 bye")
+			return
+		default:
+			tc.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+// address strips the angle brackets and parameters from a MAIL FROM or RCPT TO argument
+func address(arg string) string {
+	arg = strings.TrimSpace(arg)
+	if end := strings.Index(arg, ">"); end >= 0 {
+		arg = arg[:end]
+	}
+	return strings.TrimPrefix(arg, "<")
+}
+
+var (
+	drawDate     = time.Date(2026, time.October, 17, 20, 0, 0, 0, time.UTC)
+	nextDrawDate = time.Date(2026, time.October, 20, 20, 0, 0, 0, time.UTC)
+	jackpot      = 15000000.0
+	testGame     = models.Game{GameType: models.GameTypeLotto, NextDrawDate: &nextDrawDate, ClosestPrizeValue: &jackpot}
+	testResults  = []models.Result{
+		{DrawID: 7000, GameType: models.GameTypeLotto, DrawDate: drawDate, Results: []int{1, 2, 3, 4, 5, 6}},
+		{DrawID: 7000, GameType: models.GameTypeLottoPlus, DrawDate: drawDate, Results: []int{11, 12, 13, 14, 15, 16}},
+	}
+)
+
+// seedRecipients stores a subscriber playing a winning Lotto ticket and one who only follows the results
+func seedRecipients(t *testing.T, repo repository.Repository) {
+	t.Helper()
+	ctx := context.Background()
+
+	winner, err := repo.CreateSubscriber(ctx, models.Subscriber{Name: "winner"})
+	if err != nil {
+		t.Fatalf("CreateSubscriber() error = %v", err)
+	}
+	if _, err := repo.AddSubscriberChannel(ctx, models.SubscriberChannel{
+		SubscriberID: winner.ID, Type: models.ChannelTypeEmail, Address: "winner@example.com",
+	}); err != nil {
+		t.Fatalf("AddSubscriberChannel() error = %v", err)
+	}
+	if err := repo.Subscribe(ctx, winner.ID, models.GameTypeLotto); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if _, err := repo.CreateTicket(ctx, models.Ticket{
+		SubscriberID: winner.ID, GameType: models.GameTypeLotto, Numbers: []int{1, 2, 3, 4, 40, 41},
+	}); err != nil {
+		t.Fatalf("CreateTicket() error = %v", err)
+	}
+	if err := repo.SavePrizes(ctx, []models.Prize{
+		{DrawID: 7000, GameType: models.GameTypeLotto, Tier: 3, Winners: 1520, Amount: 215.40},
+	}); err != nil {
+		t.Fatalf("SavePrizes() error = %v", err)
+	}
+
+	follower, err := repo.CreateSubscriber(ctx, models.Subscriber{Name: "follower"})
+	if err != nil {
+		t.Fatalf("CreateSubscriber() error = %v", err)
+	}
+	if _, err := repo.AddSubscriberChannel(ctx, models.SubscriberChannel{
+		SubscriberID: follower.ID, Type: models.ChannelTypeEmail, Address: "follower@example.com",
+	}); err != nil {
+		t.Fatalf("AddSubscriberChannel() error = %v", err)
+	}
+}
+
+func newTestNotifier(t *testing.T, server *smtpServer, repo repository.Repository) *emailNotifier {
+	t.Helper()
+	n, err := New(Config{
+		Host: "127.0.0.1",
+		Port: server.port(),
+		From: "Lotto Notifications <lotto@example.com>",
+		TLS:  TLSNone,
+	}, repo)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return n.(*emailNotifier)
+}
+
+// parsedMessage is a received message split into its headers and decoded parts
+type parsedMessage struct {
+	Header mail.Header
+	Text   string
+	HTML   string
+}
+
+func parseMessage(t *testing.T, data string) parsedMessage {
+	t.Helper()
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to parse message: %v", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, want multipart/alternative", msg.Header.Get("Content-Type"))
+	}
+
+	parsed := parsedMessage{Header: msg.Header}
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read message part: %v", err)
+		}
+		// the reader decodes the quoted-printable parts
+		content, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatalf("failed to read message part: %v", err)
+		}
+		switch part.Header.Get("Content-Type") {
+		case "text/plain; charset=utf-8":
+			parsed.Text = string(content)
+		case "text/html; charset=utf-8":
+			parsed.HTML = string(content)
+		default:
+			t.Fatalf("unexpected part %q", part.Header.Get("Content-Type"))
+		}
+	}
+	return parsed
+}
+
+// golden compares got with the file in testdata, run the tests with -update to rewrite it
+func golden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s mismatch\ngot:\n%s\nwant:\n%s", name, got, want)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	server := newSMTPServer(t)
+	repo := repositorytest.New(t)
+	seedRecipients(t, repo)
+	n := newTestNotifier(t, server, repo)
+
+	if err := n.Notify(context.Background(), testGame, testResults); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	received := server.received()
+	if len(received) != 2 {
+		t.Fatalf("received %d messages, want 2", len(received))
+	}
+
+	tests := []struct {
+		to      string
+		subject string
+		golden  string
+	}{
+		{"winner@example.com", "Lotto results 2026-10-17 22:00 - you won!", "winner"},
+		{"follower@example.com", "Lotto results 2026-10-17 22:00", "follower"},
+	}
+	for idx, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			got := received[idx]
+			if got.From != "lotto@example.com" || len(got.To) != 1 || got.To[0] != tt.to {
+				t.Errorf("envelope = %s -> %v, want lotto@example.com -> [%s]", got.From, got.To, tt.to)
+			}
+
+			msg := parseMessage(t, got.Data)
+			headers := map[string]string{
+				"From":         `"Lotto Notifications" <lotto@example.com>`,
+				"To":           tt.to,
+				"Subject":      tt.subject,
+				"Mime-Version": "1.0",
+			}
+			for name, want := range headers {
+				if got := msg.Header.Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+			date, err := msg.Header.Date()
+			if err != nil || time.Since(date) > time.Minute {
+				t.Errorf("Date = %q, want the time it was sent", msg.Header.Get("Date"))
+			}
+
+			golden(t, tt.golden+".txt", msg.Text)
+			golden(t, tt.golden+".html", msg.HTML)
+		})
+	}
+}
+
+func TestNotifyContinuesAfterFailedRecipient(t *testing.T) {
+	tests := []struct {
+		name     string
+		rejected []string
+		// failingTemplate replaces the text template with one that fails for subscribers with tickets
+		failingTemplate bool
+		wantErr         string
+	}{
+		{
+			name:            "render error",
+			failingTemplate: true,
+			wantErr:         "failed to render email to winner@example.com",
+		},
+		{
+			name:     "rejected recipient",
+			rejected: []string{"winner@example.com"},
+			wantErr:  "failed to send email to winner@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newSMTPServer(t, tt.rejected...)
+			repo := repositorytest.New(t)
+			seedRecipients(t, repo)
+			n := newTestNotifier(t, server, repo)
+			if tt.failingTemplate {
+				n.text = texttemplate.Must(texttemplate.New("failing").Parse(`{{with .Matches}}{{index . 5}}{{end}}`))
+			}
+
+			err := n.Notify(context.Background(), testGame, testResults)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Notify() error = %v, want %q", err, tt.wantErr)
+			}
+
+			received := server.received()
+			if len(received) != 1 || received[0].To[0] != "follower@example.com" {
+				t.Fatalf("received %d messages, want one to follower@example.com", len(received))
+			}
+		})
+	}
+}
+
+func TestSend(t *testing.T) {
+	server := newSMTPServer(t)
+	n := newTestNotifier(t, server, repositorytest.New(t))
+
+	channel := models.SubscriberChannel{Type: models.ChannelTypeEmail, Address: "follower@example.com"}
+	msg := notifier.Message{Title: "Lotto jackpot", Text: "The Lotto jackpot is 15 000 000 PLN.\nGood luck & have fun!"}
+	if err := n.Send(context.Background(), channel, msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	received := server.received()
+	if len(received) != 1 {
+		t.Fatalf("received %d messages, want 1", len(received))
+	}
+	parsed := parseMessage(t, received[0].Data)
+	if got := parsed.Header.Get("Subject"); got != "Lotto jackpot" {
+		t.Errorf("Subject = %q, want %q", got, "Lotto jackpot")
+	}
+	if parsed.Text != msg.Text {
+		t.Errorf("text = %q, want %q", parsed.Text, msg.Text)
+	}
+	wantHTML := "<p>The Lotto jackpot is 15 000 000 PLN.<br>Good luck &amp; have fun!</p>"
+	if parsed.HTML != wantHTML {
+		t.Errorf("html = %q, want %q", parsed.HTML, wantHTML)
+	}
+}
+
+func TestSubjectEncoding(t *testing.T) {
+	msg, err := buildMessage("lotto@example.com", "follower@example.com", "Wygrana 3 zł", "text", "<p>html</p>")
+	if err != nil {
+		t.Fatalf("buildMessage() error = %v", err)
+	}
+	parsed := parseMessage(t, string(msg))
+	decoded, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil || decoded != "Wygrana 3 zł" {
+		t.Errorf("Subject = %q (%v), want %q", decoded, err, "Wygrana 3 zł")
+	}
+}
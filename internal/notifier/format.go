@@ -0,0 +1,75 @@
+package notifier
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/rules"
+)
+
+// FormatNumbers joins drawn or picked numbers the way they are printed on coupons, e.g. "3, 12, 27"
+func FormatNumbers(numbers []int) string {
+	strs := make([]string, len(numbers))
+	for i, num := range numbers {
+		strs[i] = strconv.Itoa(num)
+	}
+	return strings.Join(strs, ", ")
+}
+
+// FormatDate prints a draw date in Polish time
+func FormatDate(t time.Time) string {
+	return t.In(rules.Location()).Format("2006-01-02 15:04")
+}
+
+// FormatPLN prints an amount with thousands separated by spaces, e.g. "20 000 000 PLN"
+func FormatPLN(amount float64) string {
+	whole := int64(amount)
+	cents := int64((amount-float64(whole))*100 + 0.5)
+	if cents == 100 {
+		whole++
+		cents = 0
+	}
+
+	digits := strconv.FormatInt(whole, 10)
+	var sb strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteRune(digit)
+	}
+	if cents > 0 {
+		sb.WriteString("." + strconv.FormatInt(cents+100, 10)[1:])
+	}
+	sb.WriteString(" PLN")
+	return sb.String()
+}
+
+var gameColors = map[models.GameType]int{
+	models.GameTypeLotto:        0xF6B800,
+	models.GameTypeLottoPlus:    0x1C4FA1,
+	models.GameTypeEuroJackpot:  0xF28C00,
+	models.GameTypeMultiMulti:   0x8E24AA,
+	models.GameTypeMiniLotto:    0x43A047,
+	models.GameTypeKaskada:      0xE53935,
+	models.GameTypeEkstraPensja: 0x00897B,
+}
+
+// GameColor returns the RGB colour messages about the game are highlighted with
+func GameColor(gameType models.GameType) int {
+	if color, ok := gameColors[gameType]; ok {
+		return color
+	}
+	return 0x607D8B
+}
+
+// FormatOffset prints a duration the way it is typed, e.g. "3h", "30m" or "1h30m"
+func FormatOffset(d time.Duration) string {
+	s := strings.TrimSuffix(d.String(), "0s")
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
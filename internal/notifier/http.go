@@ -0,0 +1,78 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	maxPostAttempts = 3
+	maxRetryAfter   = time.Minute
+)
+
+// PostJSON posts the body as JSON with the extra headers, see Post
+func PostJSON(ctx context.Context, httpClient *http.Client, url string, header http.Header, body any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+	return Post(ctx, httpClient, url, header, "application/json", payload)
+}
+
+// Post posts the payload with the extra headers and retries when the receiver answers
+// 429 Too Many Requests, waiting as long as its Retry-After header asks.
+// Any other non-2xx status is an error.
+func Post(
+	ctx context.Context, httpClient *http.Client, url string, header http.Header, contentType string, payload []byte,
+) error {
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		req.Header.Set("Content-Type", contentType)
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to make request: %w", err)
+		}
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		resp.Body.Close()
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		if resp.StatusCode != http.StatusTooManyRequests || attempt == maxPostAttempts {
+			return fmt.Errorf("unexpected status code: %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+		}
+
+		wait := retryAfter(resp.Header.Get("Retry-After"))
+		slog.Warn("Rate limited, retrying", "attempt", attempt, "retryAfter", wait)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// retryAfter parses a Retry-After header given in (possibly fractional) seconds or as an HTTP date
+func retryAfter(header string) time.Duration {
+	wait := time.Second
+	if seconds, err := strconv.ParseFloat(header, 64); err == nil {
+		wait = time.Duration(seconds * float64(time.Second))
+	} else if date, err := http.ParseTime(header); err == nil {
+		wait = time.Until(date)
+	}
+	return min(max(wait, 0), maxRetryAfter)
+}
@@ -0,0 +1,55 @@
+package notifier
+
+import (
+	"context"
+	"log/slog"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/repository"
+)
+
+type logNotifier struct {
+	repo repository.Repository
+}
+
+// NewLogNotifier returns a channel that writes the results and the winning tickets to the application log
+func NewLogNotifier(repo repository.Repository) Notifier {
+	return &logNotifier{repo: repo}
+}
+
+func (n *logNotifier) Name() string {
+	return "log"
+}
+
+func (n *logNotifier) Notify(ctx context.Context, _ models.Game, results []models.Result) error {
+	for _, result := range results {
+		slog.Info("New draw results",
+			"game", result.GameType,
+			"drawID", result.DrawID,
+			"drawDate", result.DrawDate,
+			"results", result.Results,
+			"specialResults", result.SpecialResults,
+		)
+	}
+
+	matches, err := MatchTickets(ctx, n.repo, results)
+	if err != nil {
+		return err
+	}
+	for subscriberID, subscriberMatches := range matches {
+		for _, match := range subscriberMatches {
+			if !match.Won() {
+				continue
+			}
+			slog.Info("Winning ticket",
+				"subscriberID", subscriberID,
+				"ticketID", match.Ticket.ID,
+				"game", match.Result.GameType,
+				"tier", match.Tier.Name(),
+				"mainHits", match.MainHits,
+				"specialHits", match.SpecialHits,
+			)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+
+	"lotto-notifications/internal/matching"
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/repository"
+)
+
+// MatchTickets checks the registered tickets of every game in the results
+// and groups the matches by subscriber, winning matches get the prize of their tier when it is known
+func MatchTickets(
+	ctx context.Context, repo repository.Repository, results []models.Result,
+) (map[int64][]matching.Match, error) {
+	matches := map[int64][]matching.Match{}
+	for _, result := range results {
+		if !matching.Supported(result.GameType) {
+			continue
+		}
+		tickets, err := repo.GetTicketsByGame(ctx, result.GameType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tickets: %w", err)
+		}
+		prizes, err := repo.GetPrizes(ctx, result.GameType, result.DrawID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get prizes: %w", err)
+		}
+		for _, match := range matching.CheckAll(tickets, []models.Result{result}) {
+			if match.Won() {
+				match.Prize = tierPrize(prizes, match.Tier.Rank)
+			}
+			subscriberID := match.Ticket.SubscriberID
+			matches[subscriberID] = append(matches[subscriberID], match)
+		}
+	}
+	return matches, nil
+}
+
+func tierPrize(prizes []models.Prize, rank int) *models.Prize {
+	for idx := range prizes {
+		if prizes[idx].Tier == rank {
+			return &prizes[idx]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package notifier
+
+import (
+	"context"
+
+	"lotto-notifications/internal/models"
+)
+
+// Message is a free-form notification for a single subscriber, e.g. a jackpot alert
+type Message struct {
+	Title string
+	Text  string
+	// Urgent messages are delivered with a raised priority where the channel supports it
+	Urgent bool
+}
+
+// Messenger is implemented by the channels that can message a single subscriber channel directly
+type Messenger interface {
+	ChannelType() models.ChannelType
+	Send(ctx context.Context, channel models.SubscriberChannel, msg Message) error
+}
@@ -0,0 +1,133 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"lotto-notifications/internal/models"
+)
+
+// Notifier is a single notification channel that is told about newly saved draw results
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, game models.Game, results []models.Result) error
+}
+
+// Target is a single recipient of a channel, e.g. one subscriber channel or one webhook
+type Target struct {
+	// Key identifies the recipient within its channel
+	Key string
+	// MessageID is the outbox message the delivery belongs to, the same on every attempt
+	MessageID int64
+}
+
+// FanoutNotifier is a channel with many recipients. The outbox delivers to each of them separately,
+// so a recipient that failed is retried without notifying the others again.
+type FanoutNotifier interface {
+	Notifier
+	// Targets returns the keys of the recipients that should hear about the results
+	Targets(ctx context.Context, game models.Game, results []models.Result) ([]string, error)
+	// NotifyTarget delivers the results to a single recipient, skipping one that is gone
+	NotifyTarget(ctx context.Context, target Target, game models.Game, results []models.Result) error
+}
+
+// Delivery is the outcome of notifying a single channel
+type Delivery struct {
+	Channel  string
+	Err      error
+	Duration time.Duration
+}
+
+// ErrUnknownChannel is returned when notifying a channel that is not configured
+var ErrUnknownChannel = errors.New("unknown channel")
+
+// Dispatcher fans a notification out to every configured channel
+type Dispatcher interface {
+	Notifier
+	Dispatch(ctx context.Context, game models.Game, results []models.Result) []Delivery
+	// Names returns the names of the configured channels
+	Names() []string
+	// Channel returns the configured channel with the given name
+	Channel(name string) (Notifier, bool)
+}
+
+type dispatcher struct {
+	channels []Notifier
+}
+
+func NewDispatcher(channels ...Notifier) Dispatcher {
+	return &dispatcher{channels: channels}
+}
+
+func (d *dispatcher) Name() string {
+	return "dispatcher"
+}
+
+func (d *dispatcher) Names() []string {
+	names := make([]string, len(d.channels))
+	for idx, channel := range d.channels {
+		names[idx] = channel.Name()
+	}
+	return names
+}
+
+func (d *dispatcher) Channel(name string) (Notifier, bool) {
+	for _, channel := range d.channels {
+		if channel.Name() == name {
+			return channel, true
+		}
+	}
+	return nil, false
+}
+
+// Dispatch calls every channel concurrently and returns one delivery per channel,
+// in the order the channels were configured
+func (d *dispatcher) Dispatch(ctx context.Context, game models.Game, results []models.Result) []Delivery {
+	deliveries := make([]Delivery, len(d.channels))
+
+	var wg sync.WaitGroup
+	for idx, channel := range d.channels {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			err := channel.Notify(ctx, game, results)
+			deliveries[idx] = Delivery{
+				Channel:  channel.Name(),
+				Err:      err,
+				Duration: time.Since(start),
+			}
+		}()
+	}
+	wg.Wait()
+
+	return deliveries
+}
+
+// Notify dispatches to all channels, logs the outcome of each one
+// and returns the joined errors of the channels that failed
+func (d *dispatcher) Notify(ctx context.Context, game models.Game, results []models.Result) error {
+	var errs []error
+	for _, delivery := range d.Dispatch(ctx, game, results) {
+		if delivery.Err != nil {
+			slog.Error("Failed to notify channel",
+				"channel", delivery.Channel,
+				"game", game.GameType,
+				"duration", delivery.Duration,
+				"error", delivery.Err,
+			)
+			errs = append(errs, fmt.Errorf("%s: %w", delivery.Channel, delivery.Err))
+			continue
+		}
+		slog.Debug("Notified channel",
+			"channel", delivery.Channel,
+			"game", game.GameType,
+			"duration", delivery.Duration,
+		)
+	}
+	return errors.Join(errs...)
+}
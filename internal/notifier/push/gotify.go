@@ -0,0 +1,67 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/notifier"
+	"lotto-notifications/internal/repository"
+)
+
+// gotifyPriorities maps urgencies to Gotify priorities (0-10, the apps show 4 and above as notifications)
+var gotifyPriorities = map[Urgency]int{
+	UrgencyNormal:  5,
+	UrgencyJackpot: 7,
+	UrgencyWin:     9,
+}
+
+type gotifyMessage struct {
+	Title    string         `json:"title"`
+	Message  string         `json:"message"`
+	Priority int            `json:"priority"`
+	Extras   map[string]any `json:"extras,omitempty"`
+}
+
+type gotifyPublisher struct {
+	httpClient *http.Client
+	clickURL   string
+}
+
+// NewGotify returns a channel that pushes the results to the subscribers' Gotify servers
+func NewGotify(cfg Config, repo repository.Repository) notifier.Notifier {
+	return &pushNotifier{
+		channelType: models.ChannelTypeGotify,
+		cfg:         cfg,
+		repo:        repo,
+		publisher: &gotifyPublisher{
+			httpClient: newHTTPClient(),
+			clickURL:   cfg.ClickURL,
+		},
+	}
+}
+
+func (p *gotifyPublisher) publish(ctx context.Context, channel models.SubscriberChannel, msg message) error {
+	if channel.Token == nil || *channel.Token == "" {
+		return errors.New("gotify application token is not set")
+	}
+
+	body := gotifyMessage{
+		Title:    msg.Title,
+		Message:  msg.Text,
+		Priority: gotifyPriorities[msg.Urgency],
+	}
+	if p.clickURL != "" {
+		body.Extras = map[string]any{
+			"client::notification": map[string]any{
+				"click": map[string]string{"url": p.clickURL},
+			},
+		}
+	}
+
+	url := strings.TrimRight(channel.Address, "/") + "/message"
+	header := http.Header{"X-Gotify-Key": {*channel.Token}}
+	return notifier.PostJSON(ctx, p.httpClient, url, header, body)
+}
@@ -0,0 +1,51 @@
+package push
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/notifier"
+	"lotto-notifications/internal/repository"
+)
+
+// ntfyPriorities maps urgencies to ntfy priorities (1-5, 3 is the default)
+var ntfyPriorities = map[Urgency]int{
+	UrgencyNormal:  3,
+	UrgencyJackpot: 4,
+	UrgencyWin:     5,
+}
+
+type ntfyPublisher struct {
+	httpClient *http.Client
+	clickURL   string
+}
+
+// NewNtfy returns a channel that publishes the results to the subscribers' ntfy topics
+func NewNtfy(cfg Config, repo repository.Repository) notifier.Notifier {
+	return &pushNotifier{
+		channelType: models.ChannelTypeNtfy,
+		cfg:         cfg,
+		repo:        repo,
+		publisher: &ntfyPublisher{
+			httpClient: newHTTPClient(),
+			clickURL:   cfg.ClickURL,
+		},
+	}
+}
+
+func (p *ntfyPublisher) publish(ctx context.Context, channel models.SubscriberChannel, msg message) error {
+	header := http.Header{}
+	header.Set("Title", msg.Title)
+	header.Set("Priority", strconv.Itoa(ntfyPriorities[msg.Urgency]))
+	header.Set("Tags", strings.Join(msg.Tags, ","))
+	if p.clickURL != "" {
+		header.Set("Click", p.clickURL)
+	}
+	if channel.Token != nil && *channel.Token != "" {
+		header.Set("Authorization", "Bearer "+*channel.Token)
+	}
+	return notifier.Post(ctx, p.httpClient, channel.Address, header, "text/plain; charset=utf-8", []byte(msg.Text))
+}
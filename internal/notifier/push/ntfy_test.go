@@ -0,0 +1,120 @@
+package push
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"lotto-notifications/internal/models"
+)
+
+// ntfyRequest is a publish received by the fake ntfy server
+type ntfyRequest struct {
+	Header http.Header
+	Body   string
+}
+
+// newNtfyServer answers every publish with the next status, 200 OK once they run out
+func newNtfyServer(t *testing.T, statuses ...int) (*httptest.Server, func() []ntfyRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var requests []ntfyRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, ntfyRequest{Header: r.Header.Clone(), Body: string(body)})
+		status := http.StatusOK
+		if len(requests) <= len(statuses) {
+			status = statuses[len(requests)-1]
+		}
+		mu.Unlock()
+
+		if status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "0")
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []ntfyRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]ntfyRequest(nil), requests...)
+	}
+}
+
+func TestNtfyPublish(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantRequests int
+		wantErr      string
+	}{
+		{
+			name:         "published",
+			wantRequests: 1,
+		},
+		{
+			name:         "retried after rate limiting",
+			statuses:     []int{http.StatusTooManyRequests, http.StatusTooManyRequests},
+			wantRequests: 3,
+		},
+		{
+			name:         "rate limited on every attempt",
+			statuses:     []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusTooManyRequests},
+			wantRequests: 3,
+			wantErr:      "unexpected status code: 429",
+		},
+		{
+			name:         "rejected",
+			statuses:     []int{http.StatusForbidden},
+			wantRequests: 1,
+			wantErr:      "unexpected status code: 403",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newNtfyServer(t, tt.statuses...)
+			publisher := &ntfyPublisher{httpClient: server.Client(), clickURL: "https://lotto.example.com"}
+
+			token := "tk_secret"
+			channel := models.SubscriberChannel{Type: models.ChannelTypeNtfy, Address: server.URL + "/lotto", Token: &token}
+			msg := message{Title: "Lotto: you won tier III!", Text: "Lotto draw results", Urgency: UrgencyWin, Tags: []string{"game_die", "tada"}}
+
+			err := publisher.publish(context.Background(), channel, msg)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("publish() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("publish() error = %v, want %q", err, tt.wantErr)
+			}
+
+			got := requests()
+			if len(got) != tt.wantRequests {
+				t.Fatalf("requests = %d, want %d", len(got), tt.wantRequests)
+			}
+			last := got[len(got)-1]
+			wantHeaders := map[string]string{
+				"Title":         "Lotto: you won tier III!",
+				"Priority":      "5",
+				"Tags":          "game_die,tada",
+				"Click":         "https://lotto.example.com",
+				"Authorization": "Bearer tk_secret",
+				"Content-Type":  "text/plain; charset=utf-8",
+			}
+			for name, want := range wantHeaders {
+				if got := last.Header.Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+			if last.Body != "Lotto draw results" {
+				t.Errorf("body = %q, want %q", last.Body, "Lotto draw results")
+			}
+		})
+	}
+}
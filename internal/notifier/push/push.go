@@ -0,0 +1,142 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/notifier"
+	"lotto-notifications/internal/repository"
+)
+
+// Urgency decides how loudly a push is delivered
+type Urgency int
+
+const (
+	UrgencyNormal Urgency = iota
+	// UrgencyJackpot is used when the next jackpot exceeds the configured threshold
+	UrgencyJackpot
+	// UrgencyWin is used when one of the subscriber's tickets won
+	UrgencyWin
+)
+
+type Config struct {
+	// JackpotThreshold raises the urgency when the next jackpot exceeds it, zero disables it
+	JackpotThreshold float64
+	// ClickURL is opened when the push is tapped
+	ClickURL string
+}
+
+// message is a push rendered for a single recipient
+type message struct {
+	Title   string
+	Text    string
+	Urgency Urgency
+	Tags    []string
+}
+
+// publisher delivers a message to one ntfy topic or Gotify server
+type publisher interface {
+	publish(ctx context.Context, channel models.SubscriberChannel, msg message) error
+}
+
+type pushNotifier struct {
+	channelType models.ChannelType
+	cfg         Config
+	repo        repository.Repository
+	publisher   publisher
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout: 10 * time.Second,
+	}
+}
+
+func (n *pushNotifier) Name() string {
+	return string(n.channelType)
+}
+
+func (n *pushNotifier) Notify(ctx context.Context, game models.Game, results []models.Result) error {
+	recipients, err := notifier.Recipients(ctx, n.repo, n.channelType, game, results)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, recipient := range recipients {
+		if err := n.notifyRecipient(ctx, recipient, game, results); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *pushNotifier) Targets(ctx context.Context, game models.Game, results []models.Result) ([]string, error) {
+	return notifier.RecipientTargets(ctx, n.repo, n.channelType, game, results)
+}
+
+func (n *pushNotifier) NotifyTarget(
+	ctx context.Context, target notifier.Target, game models.Game, results []models.Result,
+) error {
+	recipient, ok, err := notifier.TargetRecipient(ctx, n.repo, n.channelType, target.Key, game, results)
+	if err != nil || !ok {
+		return err
+	}
+	return n.notifyRecipient(ctx, recipient, game, results)
+}
+
+func (n *pushNotifier) notifyRecipient(
+	ctx context.Context, recipient notifier.Recipient, game models.Game, results []models.Result,
+) error {
+	msg := n.newMessage(game, results, recipient)
+	if err := n.publisher.publish(ctx, recipient.Channel, msg); err != nil {
+		return fmt.Errorf("channel %d: %w", recipient.Channel.ID, err)
+	}
+	return nil
+}
+
+func (n *pushNotifier) ChannelType() models.ChannelType {
+	return n.channelType
+}
+
+func (n *pushNotifier) Send(ctx context.Context, channel models.SubscriberChannel, msg notifier.Message) error {
+	push := message{
+		Title:   msg.Title,
+		Text:    msg.Text,
+		Urgency: UrgencyNormal,
+		Tags:    []string{"bell"},
+	}
+	if msg.Urgent {
+		push.Urgency = UrgencyJackpot
+		push.Tags = []string{"moneybag"}
+	}
+	return n.publisher.publish(ctx, channel, push)
+}
+
+func (n *pushNotifier) newMessage(game models.Game, results []models.Result, recipient notifier.Recipient) message {
+	msg := message{
+		Title:   fmt.Sprintf("%s results", game.GameType),
+		Text:    notifier.ResultsText(game, results, recipient.Matches),
+		Urgency: UrgencyNormal,
+		Tags:    []string{"game_die"},
+	}
+
+	if n.cfg.JackpotThreshold > 0 && game.ClosestPrizeValue != nil && *game.ClosestPrizeValue >= n.cfg.JackpotThreshold {
+		msg.Urgency = UrgencyJackpot
+		msg.Title = fmt.Sprintf("%s results, jackpot %s", game.GameType, notifier.FormatPLN(*game.ClosestPrizeValue))
+		msg.Tags = append(msg.Tags, "moneybag")
+	}
+	for _, match := range recipient.Matches {
+		if match.Won() {
+			msg.Urgency = UrgencyWin
+			msg.Title = fmt.Sprintf("%s: you won tier %s!", game.GameType, match.Tier.Name())
+			msg.Tags = append(msg.Tags, "tada")
+			break
+		}
+	}
+	return msg
+}
@@ -0,0 +1,135 @@
+package notifier
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"lotto-notifications/internal/matching"
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/repository"
+)
+
+// Recipient is a subscriber channel the results are delivered to,
+// together with the subscriber's checked tickets
+type Recipient struct {
+	Channel models.SubscriberChannel
+	Matches []matching.Match
+}
+
+// Recipients returns the channels of the given type whose subscribers should hear about the results.
+// A subscriber is interested when they subscribed to one of the games, play a ticket in one of them
+// or did not subscribe to anything, which means every game.
+func Recipients(
+	ctx context.Context,
+	repo repository.Repository,
+	channelType models.ChannelType,
+	game models.Game,
+	results []models.Result,
+) ([]Recipient, error) {
+	channels, err := repo.GetChannelsByType(ctx, channelType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s channels: %w", channelType, err)
+	}
+	if len(channels) == 0 {
+		return nil, nil
+	}
+	return interested(ctx, repo, channels, game, results)
+}
+
+// RecipientTargets returns the IDs of the channels Recipients would return, as FanoutNotifier targets
+func RecipientTargets(
+	ctx context.Context,
+	repo repository.Repository,
+	channelType models.ChannelType,
+	game models.Game,
+	results []models.Result,
+) ([]string, error) {
+	recipients, err := Recipients(ctx, repo, channelType, game, results)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]string, len(recipients))
+	for idx, recipient := range recipients {
+		targets[idx] = strconv.FormatInt(recipient.Channel.ID, 10)
+	}
+	return targets, nil
+}
+
+// TargetRecipient returns the recipient behind a target returned by RecipientTargets.
+// It reports false when the channel was removed or its subscriber lost interest in the results since.
+func TargetRecipient(
+	ctx context.Context,
+	repo repository.Repository,
+	channelType models.ChannelType,
+	target string,
+	game models.Game,
+	results []models.Result,
+) (Recipient, bool, error) {
+	id, err := strconv.ParseInt(target, 10, 64)
+	if err != nil {
+		return Recipient{}, false, fmt.Errorf("invalid channel id %q", target)
+	}
+	channel, err := repo.GetSubscriberChannel(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Recipient{}, false, nil
+	}
+	if err != nil {
+		return Recipient{}, false, fmt.Errorf("failed to get channel %d: %w", id, err)
+	}
+	if channel.Type != channelType {
+		return Recipient{}, false, nil
+	}
+
+	recipients, err := interested(ctx, repo, []models.SubscriberChannel{channel}, game, results)
+	if err != nil || len(recipients) == 0 {
+		return Recipient{}, false, err
+	}
+	return recipients[0], true, nil
+}
+
+// interested returns the channels whose subscribers should hear about the results, see Recipients
+func interested(
+	ctx context.Context,
+	repo repository.Repository,
+	channels []models.SubscriberChannel,
+	game models.Game,
+	results []models.Result,
+) ([]Recipient, error) {
+	subscriptions, err := repo.GetSubscriptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscriptions: %w", err)
+	}
+	games := map[models.GameType]struct{}{game.GameType: {}}
+	for _, result := range results {
+		games[result.GameType] = struct{}{}
+	}
+	hasSubscriptions := map[int64]bool{}
+	subscribed := map[int64]bool{}
+	for _, subscription := range subscriptions {
+		hasSubscriptions[subscription.SubscriberID] = true
+		if _, ok := games[subscription.GameType]; ok {
+			subscribed[subscription.SubscriberID] = true
+		}
+	}
+
+	matches, err := MatchTickets(ctx, repo, results)
+	if err != nil {
+		return nil, err
+	}
+
+	recipients := []Recipient{}
+	for _, channel := range channels {
+		subscriberID := channel.SubscriberID
+		if hasSubscriptions[subscriberID] && !subscribed[subscriberID] && len(matches[subscriberID]) == 0 {
+			continue
+		}
+		recipients = append(recipients, Recipient{
+			Channel: channel,
+			Matches: matches[subscriberID],
+		})
+	}
+	return recipients, nil
+}
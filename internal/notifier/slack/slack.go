@@ -0,0 +1,100 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/notifier"
+)
+
+type text struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type block struct {
+	Type     string `json:"type"`
+	Text     *text  `json:"text,omitempty"`
+	Fields   []text `json:"fields,omitempty"`
+	Elements []text `json:"elements,omitempty"`
+}
+
+// attachment wraps the blocks so the message gets the game's colour bar
+type attachment struct {
+	Color  string  `json:"color"`
+	Blocks []block `json:"blocks"`
+}
+
+type message struct {
+	Text        string       `json:"text"`
+	Attachments []attachment `json:"attachments"`
+}
+
+type slackNotifier struct {
+	httpClient *http.Client
+	webhookURL string
+}
+
+// New returns a channel that posts the results to a Slack incoming webhook
+func New(webhookURL string) notifier.Notifier {
+	return &slackNotifier{
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		webhookURL: webhookURL,
+	}
+}
+
+func (n *slackNotifier) Name() string {
+	return "slack"
+}
+
+func (n *slackNotifier) Notify(ctx context.Context, game models.Game, results []models.Result) error {
+	return notifier.PostJSON(ctx, n.httpClient, n.webhookURL, nil, newMessage(game, results))
+}
+
+func newMessage(game models.Game, results []models.Result) message {
+	title := fmt.Sprintf("%s draw results", game.GameType)
+	blocks := []block{
+		{Type: "header", Text: &text{Type: "plain_text", Text: title}},
+	}
+
+	for _, result := range results {
+		fields := []text{
+			{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\nDraw %d, %s", result.GameType, result.DrawID, notifier.FormatDate(result.DrawDate))},
+			{Type: "mrkdwn", Text: "*Numbers*\n" + formatNumbers(result.Results)},
+		}
+		if len(result.SpecialResults) > 0 {
+			fields = append(fields, text{Type: "mrkdwn", Text: "*Special numbers*\n" + formatNumbers(result.SpecialResults)})
+		}
+		blocks = append(blocks, block{Type: "section", Fields: fields})
+	}
+
+	var footer []text
+	if game.NextDrawDate != nil {
+		footer = append(footer, text{Type: "mrkdwn", Text: "Next draw: *" + notifier.FormatDate(*game.NextDrawDate) + "*"})
+	}
+	if game.ClosestPrizeValue != nil {
+		footer = append(footer, text{Type: "mrkdwn", Text: "Jackpot: *" + notifier.FormatPLN(*game.ClosestPrizeValue) + "*"})
+	}
+	if len(footer) > 0 {
+		blocks = append(blocks, block{Type: "context", Elements: footer})
+	}
+
+	return message{
+		Text: title,
+		Attachments: []attachment{{
+			Color:  fmt.Sprintf("#%06X", notifier.GameColor(game.GameType)),
+			Blocks: blocks,
+		}},
+	}
+}
+
+func formatNumbers(numbers []int) string {
+	strs := strings.Split(notifier.FormatNumbers(numbers), ", ")
+	return "`" + strings.Join(strs, "` `") + "`"
+}
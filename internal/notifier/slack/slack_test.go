@@ -0,0 +1,161 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"lotto-notifications/internal/models"
+)
+
+var drawDate = time.Date(2026, time.October, 17, 20, 0, 0, 0, time.UTC)
+
+func lottoResults() (models.Game, []models.Result) {
+	nextDrawDate := time.Date(2026, time.October, 20, 20, 0, 0, 0, time.UTC)
+	jackpot := 15_000_000.0
+	game := models.Game{GameType: models.GameTypeLotto, NextDrawDate: &nextDrawDate, ClosestPrizeValue: &jackpot}
+	results := []models.Result{
+		{DrawID: 7000, GameType: models.GameTypeLotto, DrawDate: drawDate, Results: models.IntSlice{3, 12, 27, 31, 40, 49}},
+		{DrawID: 7000, GameType: models.GameTypeLottoPlus, DrawDate: drawDate, Results: models.IntSlice{1, 2, 3, 4, 5, 6}},
+	}
+	return game, results
+}
+
+func TestNewMessage(t *testing.T) {
+	game, results := lottoResults()
+	payload, err := json.Marshal(newMessage(game, results))
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var got message
+	if err := json.Unmarshal(payload, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if got.Text != "Lotto draw results" {
+		t.Errorf("text = %q, want the title as the notification fallback", got.Text)
+	}
+	if len(got.Attachments) != 1 {
+		t.Fatalf("got %d attachments, want 1", len(got.Attachments))
+	}
+	attachment := got.Attachments[0]
+	if attachment.Color != "#F6B800" {
+		t.Errorf("color = %q, want the Lotto colour #F6B800", attachment.Color)
+	}
+
+	want := []block{
+		{Type: "header", Text: &text{Type: "plain_text", Text: "Lotto draw results"}},
+		{Type: "section", Fields: []text{
+			{Type: "mrkdwn", Text: "*Lotto*\nDraw 7000, 2026-10-17 22:00"},
+			{Type: "mrkdwn", Text: "*Numbers*\n`3` `12` `27` `31` `40` `49`"},
+		}},
+		{Type: "section", Fields: []text{
+			{Type: "mrkdwn", Text: "*LottoPlus*\nDraw 7000, 2026-10-17 22:00"},
+			{Type: "mrkdwn", Text: "*Numbers*\n`1` `2` `3` `4` `5` `6`"},
+		}},
+		{Type: "context", Elements: []text{
+			{Type: "mrkdwn", Text: "Next draw: *2026-10-20 22:00*"},
+			{Type: "mrkdwn", Text: "Jackpot: *15 000 000 PLN*"},
+		}},
+	}
+	if len(attachment.Blocks) != len(want) {
+		t.Fatalf("got %d blocks, want %d: %s", len(attachment.Blocks), len(want), payload)
+	}
+	for idx := range want {
+		gotBlock, _ := json.Marshal(attachment.Blocks[idx])
+		wantBlock, _ := json.Marshal(want[idx])
+		if string(gotBlock) != string(wantBlock) {
+			t.Errorf("block %d = %s\nwant %s", idx, gotBlock, wantBlock)
+		}
+	}
+}
+
+func TestNewMessageSpecialNumbers(t *testing.T) {
+	game := models.Game{GameType: models.GameTypeEuroJackpot}
+	results := []models.Result{{
+		DrawID:         900,
+		GameType:       models.GameTypeEuroJackpot,
+		DrawDate:       drawDate,
+		Results:        models.IntSlice{5, 11, 23, 38, 44},
+		SpecialResults: models.IntSlice{2, 9},
+	}}
+
+	msg := newMessage(game, results)
+	if color := msg.Attachments[0].Color; color != "#F28C00" {
+		t.Errorf("color = %q, want the EuroJackpot colour #F28C00", color)
+	}
+	blocks := msg.Attachments[0].Blocks
+	if len(blocks) != 2 {
+		t.Fatalf("got %d blocks, want the header and one section without a footer", len(blocks))
+	}
+	fields := blocks[1].Fields
+	if len(fields) != 3 || fields[2].Text != "*Special numbers*\n`2` `9`" {
+		t.Errorf("fields = %+v, want the special numbers last", fields)
+	}
+}
+
+func TestNotifyRetriesRateLimited(t *testing.T) {
+	var mu sync.Mutex
+	var bodies [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, body)
+		first := len(bodies) == 1
+		mu.Unlock()
+
+		if first {
+			w.Header().Set("Retry-After", "0.01")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	game, results := lottoResults()
+	if err := New(server.URL).Notify(context.Background(), game, results); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(bodies) != 2 {
+		t.Fatalf("received %d requests, want 2", len(bodies))
+	}
+	if string(bodies[0]) != string(bodies[1]) {
+		t.Errorf("retried body %s differs from %s", bodies[1], bodies[0])
+	}
+	var msg message
+	if err := json.Unmarshal(bodies[1], &msg); err != nil || msg.Text != "Lotto draw results" {
+		t.Errorf("posted %s, %v, want the Lotto message", bodies[1], err)
+	}
+}
+
+func TestNotifyGivesUpWhenStillRateLimited(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	t.Cleanup(server.Close)
+
+	game, results := lottoResults()
+	if err := New(server.URL).Notify(context.Background(), game, results); err == nil {
+		t.Fatal("Notify() succeeded, want the rate limit error")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if requests != 3 {
+		t.Errorf("received %d requests, want 3", requests)
+	}
+}
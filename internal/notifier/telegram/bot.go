@@ -0,0 +1,139 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/notifier"
+	"lotto-notifications/internal/repository"
+)
+
+const DefaultBaseURL = "https://api.telegram.org"
+
+// Bot posts results to the subscribed chats and answers commands sent to it
+type Bot interface {
+	notifier.Notifier
+	// Run long-polls for commands until the context is cancelled
+	Run(ctx context.Context)
+}
+
+type bot struct {
+	client *client
+	repo   repository.Repository
+	// reminderOffsets are used by /remind when no offsets are given
+	reminderOffsets []time.Duration
+}
+
+func New(baseURL, token string, repo repository.Repository, reminderOffsets []time.Duration) Bot {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &bot{
+		client: &client{
+			httpClient: &http.Client{
+				Timeout: pollTimeout + 10*time.Second,
+			},
+			baseURL: baseURL,
+			token:   token,
+		},
+		repo:            repo,
+		reminderOffsets: reminderOffsets,
+	}
+}
+
+func (b *bot) Name() string {
+	return string(models.ChannelTypeTelegram)
+}
+
+func (b *bot) Notify(ctx context.Context, game models.Game, results []models.Result) error {
+	recipients, err := notifier.Recipients(ctx, b.repo, models.ChannelTypeTelegram, game, results)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, recipient := range recipients {
+		if err := b.notifyRecipient(ctx, recipient, game, results); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (b *bot) Targets(ctx context.Context, game models.Game, results []models.Result) ([]string, error) {
+	return notifier.RecipientTargets(ctx, b.repo, models.ChannelTypeTelegram, game, results)
+}
+
+func (b *bot) NotifyTarget(ctx context.Context, target notifier.Target, game models.Game, results []models.Result) error {
+	recipient, ok, err := notifier.TargetRecipient(ctx, b.repo, models.ChannelTypeTelegram, target.Key, game, results)
+	if err != nil || !ok {
+		return err
+	}
+	return b.notifyRecipient(ctx, recipient, game, results)
+}
+
+func (b *bot) notifyRecipient(
+	ctx context.Context, recipient notifier.Recipient, game models.Game, results []models.Result,
+) error {
+	chatID, err := strconv.ParseInt(recipient.Channel.Address, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid chat id %q", recipient.Channel.Address)
+	}
+	text := notifier.ResultsText(game, results, recipient.Matches)
+	if err := b.client.sendMessage(ctx, chatID, text); err != nil {
+		return fmt.Errorf("chat %d: %w", chatID, err)
+	}
+	return nil
+}
+
+func (b *bot) ChannelType() models.ChannelType {
+	return models.ChannelTypeTelegram
+}
+
+func (b *bot) Send(ctx context.Context, channel models.SubscriberChannel, msg notifier.Message) error {
+	chatID, err := strconv.ParseInt(channel.Address, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid chat id %q", channel.Address)
+	}
+	return b.client.sendMessage(ctx, chatID, msg.Title+"\n\n"+msg.Text)
+}
+
+func (b *bot) Run(ctx context.Context) {
+	slog.Info("Telegram bot listening for commands")
+	var offset int64
+	for {
+		updates, err := b.client.getUpdates(ctx, offset)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			slog.Error("Failed to get telegram updates", "error", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(5 * time.Second):
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+			if update.Message == nil || update.Message.Text == "" {
+				continue
+			}
+			reply := b.handle(ctx, *update.Message)
+			if reply == "" {
+				continue
+			}
+			if err := b.client.sendMessage(ctx, update.Message.Chat.ID, reply); err != nil {
+				slog.Error("Failed to reply to telegram command", "chatID", update.Message.Chat.ID, "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,411 @@
+package telegram
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/repository"
+	"lotto-notifications/internal/repository/repositorytest"
+)
+
+const testToken = "123:secret"
+
+type sentMessage struct {
+	ChatID int64  `json:"chat_id"`
+	Text   string `json:"text"`
+}
+
+// botAPI is a stand-in for the Telegram Bot API, it serves the queued updates once
+// and records the messages sent by the bot
+type botAPI struct {
+	server *httptest.Server
+	// blocked chats answer sendMessage the way Telegram does when the bot was blocked
+	blocked map[int64]bool
+
+	mu      sync.Mutex
+	updates []update
+	sent    []sentMessage
+}
+
+func newBotAPI(t *testing.T, blocked ...int64) *botAPI {
+	t.Helper()
+	api := &botAPI{blocked: map[int64]bool{}}
+	for _, chatID := range blocked {
+		api.blocked[chatID] = true
+	}
+	api.server = httptest.NewServer(http.HandlerFunc(api.handle))
+	t.Cleanup(api.server.Close)
+	return api
+}
+
+func (api *botAPI) handle(w http.ResponseWriter, r *http.Request) {
+	method, ok := strings.CutPrefix(r.URL.Path, "/bot"+testToken+"/")
+	if !ok || r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(apiResponse[any]{Description: "Not Found"})
+		return
+	}
+
+	switch method {
+	case "sendMessage":
+		var msg sentMessage
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(apiResponse[any]{Description: err.Error()})
+			return
+		}
+		if api.blocked[msg.ChatID] {
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(apiResponse[any]{Description: "Forbidden: bot was blocked by the user"})
+			return
+		}
+		api.mu.Lock()
+		api.sent = append(api.sent, msg)
+		api.mu.Unlock()
+		json.NewEncoder(w).Encode(apiResponse[message]{OK: true, Result: message{Chat: chat{ID: msg.ChatID}, Text: msg.Text}})
+	case "getUpdates":
+		var params struct {
+			Offset int64 `json:"offset"`
+		}
+		json.NewDecoder(r.Body).Decode(&params)
+		api.mu.Lock()
+		updates := []update{}
+		for _, u := range api.updates {
+			if u.UpdateID >= params.Offset {
+				updates = append(updates, u)
+			}
+		}
+		api.mu.Unlock()
+		if len(updates) == 0 {
+			// a short long-poll keeps the bot from spinning
+			select {
+			case <-r.Context().Done():
+			case <-time.After(20 * time.Millisecond):
+			}
+		}
+		json.NewEncoder(w).Encode(apiResponse[[]update]{OK: true, Result: updates})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(apiResponse[any]{Description: "Not Found: method not found"})
+	}
+}
+
+func (api *botAPI) queue(chatID int64, texts ...string) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	for _, text := range texts {
+		id := int64(len(api.updates) + 1)
+		api.updates = append(api.updates, update{
+			UpdateID: id,
+			Message:  &message{MessageID: id, Chat: chat{ID: chatID, Type: "private", FirstName: "Ala"}, Text: text},
+		})
+	}
+}
+
+func (api *botAPI) sentMessages() []sentMessage {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	return append([]sentMessage(nil), api.sent...)
+}
+
+func newTestBot(t *testing.T, api *botAPI, repo repository.Repository) *bot {
+	t.Helper()
+	return New(api.server.URL, testToken, repo, []time.Duration{time.Hour}).(*bot)
+}
+
+// addChat stores a subscriber reached at the telegram chat address
+func addChat(t *testing.T, repo repository.Repository, address string, games ...models.GameType) int64 {
+	t.Helper()
+	ctx := context.Background()
+	subscriber, err := repo.CreateSubscriber(ctx, models.Subscriber{Name: "chat " + address})
+	if err != nil {
+		t.Fatalf("CreateSubscriber() error = %v", err)
+	}
+	if _, err := repo.AddSubscriberChannel(ctx, models.SubscriberChannel{
+		SubscriberID: subscriber.ID, Type: models.ChannelTypeTelegram, Address: address,
+	}); err != nil {
+		t.Fatalf("AddSubscriberChannel() error = %v", err)
+	}
+	for _, game := range games {
+		if err := repo.Subscribe(ctx, subscriber.ID, game); err != nil {
+			t.Fatalf("Subscribe() error = %v", err)
+		}
+	}
+	return subscriber.ID
+}
+
+func TestNotify(t *testing.T) {
+	api := newBotAPI(t, 104)
+	repo := repositorytest.New(t)
+	ctx := context.Background()
+
+	winner := addChat(t, repo, "101", models.GameTypeLotto)
+	if _, err := repo.CreateTicket(ctx, models.Ticket{
+		SubscriberID: winner, GameType: models.GameTypeLotto, Numbers: []int{1, 2, 3, 4, 40, 41},
+	}); err != nil {
+		t.Fatalf("CreateTicket() error = %v", err)
+	}
+	if err := repo.SavePrizes(ctx, []models.Prize{
+		{DrawID: 7000, GameType: models.GameTypeLotto, Tier: 3, Winners: 1520, Amount: 215.40},
+	}); err != nil {
+		t.Fatalf("SavePrizes() error = %v", err)
+	}
+	// subscribed to another game only
+	addChat(t, repo, "102", models.GameTypeMiniLotto)
+	// no subscriptions means every game
+	addChat(t, repo, "103")
+	// blocked the bot
+	addChat(t, repo, "104")
+	addChat(t, repo, "not-a-chat")
+
+	drawDate := time.Date(2026, time.October, 17, 20, 0, 0, 0, time.UTC)
+	nextDrawDate := time.Date(2026, time.October, 20, 20, 0, 0, 0, time.UTC)
+	jackpot := 15000000.0
+	game := models.Game{GameType: models.GameTypeLotto, NextDrawDate: &nextDrawDate, ClosestPrizeValue: &jackpot}
+	results := []models.Result{
+		{DrawID: 7000, GameType: models.GameTypeLotto, DrawDate: drawDate, Results: []int{1, 2, 3, 4, 5, 6}},
+	}
+
+	b := newTestBot(t, api, repo)
+	err := b.Notify(ctx, game, results)
+	if err == nil || !strings.Contains(err.Error(), "chat 104") || !strings.Contains(err.Error(), `invalid chat id "not-a-chat"`) {
+		t.Errorf("Notify() error = %v, want errors for chat 104 and not-a-chat", err)
+	}
+
+	want := []sentMessage{
+		{ChatID: 101, Text: "Lotto draw results\n\n" +
+			"Lotto, draw 7000 on 2026-10-17 22:00\n" +
+			"Numbers: 1, 2, 3, 4, 5, 6\n\n" +
+			"Your tickets:\n" +
+			"- Lotto 1, 2, 3, 4, 40, 41: matched 4 — tier III paid 215.40 PLN this draw\n\n" +
+			"Next draw: 2026-10-20 22:00\n" +
+			"Next jackpot: 15 000 000 PLN"},
+		{ChatID: 103, Text: "Lotto draw results\n\n" +
+			"Lotto, draw 7000 on 2026-10-17 22:00\n" +
+			"Numbers: 1, 2, 3, 4, 5, 6\n\n" +
+			"Next draw: 2026-10-20 22:00\n" +
+			"Next jackpot: 15 000 000 PLN"},
+	}
+	sent := api.sentMessages()
+	if len(sent) != len(want) {
+		t.Fatalf("sent %d messages, want %d: %+v", len(sent), len(want), sent)
+	}
+	for idx := range want {
+		if sent[idx] != want[idx] {
+			t.Errorf("message %d = %+v\nwant %+v", idx, sent[idx], want[idx])
+		}
+	}
+}
+
+func TestCommands(t *testing.T) {
+	repo := repositorytest.New(t)
+	b := newTestBot(t, newBotAPI(t), repo)
+	ctx := context.Background()
+	chatID := int64(42)
+
+	// channel returns the chat's channel and its subscriptions, ok is false when the chat is not registered
+	channel := func() (models.SubscriberChannel, []models.GameType, bool) {
+		t.Helper()
+		channel, err := repo.GetChannelByAddress(ctx, models.ChannelTypeTelegram, strconv.FormatInt(chatID, 10))
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.SubscriberChannel{}, nil, false
+		}
+		if err != nil {
+			t.Fatalf("GetChannelByAddress() error = %v", err)
+		}
+		subscriptions, err := repo.GetSubscriptions(ctx)
+		if err != nil {
+			t.Fatalf("GetSubscriptions() error = %v", err)
+		}
+		var games []models.GameType
+		for _, subscription := range subscriptions {
+			if subscription.SubscriberID == channel.SubscriberID {
+				games = append(games, subscription.GameType)
+			}
+		}
+		return channel, games, true
+	}
+
+	steps := []struct {
+		text           string
+		wantReply      string
+		wantRegistered bool
+		wantGames      []models.GameType
+	}{
+		{
+			text:      "/stop",
+			wantReply: "I am not sending anything to this chat",
+		},
+		// commands that only read or remove settings do not sign the chat up
+		{text: "/tickets", wantReply: notRegisteredText},
+		{text: "/alerts", wantReply: notRegisteredText},
+		{text: "/reminders", wantReply: notRegisteredText},
+		{text: "/unsubscribe Lotto", wantReply: notRegisteredText},
+		{text: "/ticket remove 1", wantReply: notRegisteredText},
+		{text: "/alert Lotto 20mln", wantReply: notRegisteredText},
+		{text: "/remind off Lotto", wantReply: notRegisteredText},
+		{
+			text:           "/start",
+			wantReply:      startText,
+			wantRegistered: true,
+		},
+		{
+			text:           "/subscribe MiniLotto",
+			wantReply:      "Subscribed to MiniLotto results.",
+			wantRegistered: true,
+			wantGames:      []models.GameType{models.GameTypeMiniLotto},
+		},
+		{
+			text:           "/subscribe@LottoBot eurojackpot",
+			wantReply:      "Subscribed to EuroJackpot results.",
+			wantRegistered: true,
+			wantGames:      []models.GameType{models.GameTypeEuroJackpot, models.GameTypeMiniLotto},
+		},
+		{
+			text:           "/subscribe Keno",
+			wantReply:      `Unknown game "Keno"`,
+			wantRegistered: true,
+			wantGames:      []models.GameType{models.GameTypeEuroJackpot, models.GameTypeMiniLotto},
+		},
+		{
+			text:           "/subscribe",
+			wantReply:      "Which game?",
+			wantRegistered: true,
+			wantGames:      []models.GameType{models.GameTypeEuroJackpot, models.GameTypeMiniLotto},
+		},
+		{
+			text:           "/alert LottoPlus 5mln",
+			wantReply:      "LottoPlus has no jackpot of its own, it is drawn together with Lotto.",
+			wantRegistered: true,
+			wantGames:      []models.GameType{models.GameTypeEuroJackpot, models.GameTypeMiniLotto},
+		},
+		{
+			text:           "/alert lotto 20mln",
+			wantReply:      "I will tell you once the Lotto jackpot reaches",
+			wantRegistered: true,
+			wantGames:      []models.GameType{models.GameTypeEuroJackpot, models.GameTypeMiniLotto},
+		},
+		{
+			text:           "/start",
+			wantReply:      startText,
+			wantRegistered: true,
+			wantGames:      []models.GameType{models.GameTypeEuroJackpot, models.GameTypeMiniLotto},
+		},
+		{
+			text:      "/stop",
+			wantReply: "Stopped, I will not send you anything anymore.",
+		},
+		{
+			text:      "/stop",
+			wantReply: "I am not sending anything to this chat",
+		},
+		{text: "/tickets", wantReply: notRegisteredText},
+	}
+
+	var subscriberIDs []int64
+	for _, step := range steps {
+		reply := b.handle(ctx, message{Chat: chat{ID: chatID, FirstName: "Ala"}, Text: step.text})
+		if !strings.HasPrefix(reply, step.wantReply) {
+			t.Errorf("%s: reply = %q, want it to start with %q", step.text, reply, step.wantReply)
+		}
+
+		channel, games, registered := channel()
+		if registered != step.wantRegistered {
+			t.Errorf("%s: registered = %t, want %t", step.text, registered, step.wantRegistered)
+		}
+		if registered {
+			subscriberIDs = append(subscriberIDs, channel.SubscriberID)
+		}
+		if strings.Join(gameStrings(games), ",") != strings.Join(gameStrings(step.wantGames), ",") {
+			t.Errorf("%s: subscriptions = %v, want %v", step.text, games, step.wantGames)
+		}
+	}
+
+	for _, id := range subscriberIDs[1:] {
+		if id != subscriberIDs[0] {
+			t.Errorf("chat registered as subscribers %v, want a single subscriber", subscriberIDs)
+			break
+		}
+	}
+	if _, err := repo.GetSubscriber(ctx, subscriberIDs[0]); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetSubscriber() after /stop error = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestStopKeepsSubscriberWithOtherChannels(t *testing.T) {
+	repo := repositorytest.New(t)
+	b := newTestBot(t, newBotAPI(t), repo)
+	ctx := context.Background()
+
+	subscriberID := addChat(t, repo, "42", models.GameTypeLotto)
+	if _, err := repo.AddSubscriberChannel(ctx, models.SubscriberChannel{
+		SubscriberID: subscriberID, Type: models.ChannelTypeEmail, Address: "ala@example.com",
+	}); err != nil {
+		t.Fatalf("AddSubscriberChannel() error = %v", err)
+	}
+
+	b.handle(ctx, message{Chat: chat{ID: 42}, Text: "/stop"})
+
+	channels, err := repo.GetSubscriberChannels(ctx, subscriberID)
+	if err != nil {
+		t.Fatalf("GetSubscriberChannels() error = %v", err)
+	}
+	if len(channels) != 1 || channels[0].Type != models.ChannelTypeEmail {
+		t.Errorf("channels = %+v, want only the email channel", channels)
+	}
+}
+
+func TestRun(t *testing.T) {
+	api := newBotAPI(t)
+	repo := repositorytest.New(t)
+	b := newTestBot(t, api, repo)
+
+	api.queue(42, "/start", "hello", "/subscribe MiniLotto", "/stop")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		b.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.After(5 * time.Second)
+	for len(api.sentMessages()) < 3 {
+		select {
+		case <-deadline:
+			t.Fatalf("bot replied %d times, want 3", len(api.sentMessages()))
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	cancel()
+	<-done
+
+	sent := api.sentMessages()
+	wantPrefixes := []string{startText, "Subscribed to MiniLotto results.", "Stopped,"}
+	if len(sent) != len(wantPrefixes) {
+		t.Fatalf("sent %d messages, want %d: %+v", len(sent), len(wantPrefixes), sent)
+	}
+	for idx, prefix := range wantPrefixes {
+		if sent[idx].ChatID != 42 || !strings.HasPrefix(sent[idx].Text, prefix) {
+			t.Errorf("reply %d = %+v, want one to chat 42 starting with %q", idx, sent[idx], prefix)
+		}
+	}
+}
+
+func gameStrings(games []models.GameType) []string {
+	strs := make([]string, len(games))
+	for idx, game := range games {
+		strs[idx] = string(game)
+	}
+	return strs
+}
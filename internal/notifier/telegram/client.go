@@ -0,0 +1,102 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// pollTimeout is how long the Bot API holds a getUpdates request open
+const pollTimeout = 30 * time.Second
+
+type apiResponse[T any] struct {
+	OK          bool   `json:"ok"`
+	Result      T      `json:"result"`
+	Description string `json:"description"`
+}
+
+type chat struct {
+	ID        int64  `json:"id"`
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Username  string `json:"username"`
+	FirstName string `json:"first_name"`
+}
+
+type message struct {
+	MessageID int64  `json:"message_id"`
+	Chat      chat   `json:"chat"`
+	Text      string `json:"text"`
+}
+
+type update struct {
+	UpdateID int64    `json:"update_id"`
+	Message  *message `json:"message"`
+}
+
+// client is a minimal Telegram Bot API client
+type client struct {
+	httpClient *http.Client
+	baseURL    string
+	token      string
+}
+
+func (c *client) call(ctx context.Context, method string, params any, result any) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/bot%s/%s", c.baseURL, c.token, method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// the token is part of the URL, so the error is not wrapped to keep it out of the logs
+		return fmt.Errorf("failed to make %s request", method)
+	}
+	defer resp.Body.Close()
+
+	var response apiResponse[json.RawMessage]
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("failed to decode %s response (status %d): %w", method, resp.StatusCode, err)
+	}
+	if !response.OK {
+		return fmt.Errorf("%s failed (status %d): %s", method, resp.StatusCode, response.Description)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("failed to decode %s result: %w", method, err)
+	}
+	return nil
+}
+
+func (c *client) sendMessage(ctx context.Context, chatID int64, text string) error {
+	params := map[string]any{
+		"chat_id": chatID,
+		"text":    text,
+	}
+	return c.call(ctx, "sendMessage", params, nil)
+}
+
+func (c *client) getUpdates(ctx context.Context, offset int64) ([]update, error) {
+	params := map[string]any{
+		"offset":          offset,
+		"timeout":         int(pollTimeout.Seconds()),
+		"allowed_updates": []string{"message"},
+	}
+	var updates []update
+	if err := c.call(ctx, "getUpdates", params, &updates); err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
@@ -0,0 +1,585 @@
+package telegram
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"lotto-notifications/internal/matching"
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/notifier"
+	"lotto-notifications/internal/repository"
+	"lotto-notifications/internal/rules"
+)
+
+const helpText = `Commands:
+/start - get the results of every game
+/stop - stop all messages and forget this chat
+/latest GAME - latest draw results
+/next GAME - next draw date and jackpot
+/subscribe GAME - get the results of a game
+/unsubscribe GAME - stop getting the results of a game
+/ticket add GAME NUMBERS [+ SPECIAL NUMBERS] - check a ticket after every draw
+/ticket remove ID - stop checking a ticket
+/tickets - list your tickets
+/alert GAME AMOUNT - tell me once the jackpot reaches AMOUNT PLN, e.g. /alert Lotto 20mln
+/alert remove GAME - remove a jackpot alert
+/alerts - list your jackpot alerts
+/remind GAME [OFFSETS] - remind me before every draw, e.g. /remind Lotto 3h 30m
+/remind off GAME - stop the reminders
+/reminders - list your reminders
+
+Games: %s`
+
+// maxReminderOffset keeps reminders within the usual time between draws
+const maxReminderOffset = 72 * time.Hour
+
+const internalErrorText = "Something went wrong, please try again later."
+
+const notRegisteredText = "I am not sending anything to this chat, send /start first."
+
+const startText = "Hi! I will send you the results of every game after each draw, " +
+	"use /subscribe to pick the games and /stop to stop the messages."
+
+// handle runs a command and returns the reply, an empty reply means the message is ignored
+func (b *bot) handle(ctx context.Context, msg message) string {
+	fields := strings.Fields(msg.Text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return ""
+	}
+	// in groups commands are addressed as /command@BotName
+	command, _, _ := strings.Cut(strings.ToLower(fields[0]), "@")
+	args := fields[1:]
+
+	reply, err := b.runCommand(ctx, msg.Chat, command, args)
+	if err != nil {
+		slog.Error("Failed to handle telegram command", "command", command, "chatID", msg.Chat.ID, "error", err)
+		return internalErrorText
+	}
+	return reply
+}
+
+func (b *bot) runCommand(ctx context.Context, chat chat, command string, args []string) (string, error) {
+	switch command {
+	case "/start":
+		return b.start(ctx, chat)
+	case "/stop":
+		return b.stop(ctx, chat)
+	case "/help":
+		return fmt.Sprintf(helpText, strings.Join(gameNames(), ", ")), nil
+	case "/latest":
+		return b.latest(ctx, args)
+	case "/next":
+		return b.next(ctx, args)
+	case "/subscribe":
+		return b.subscribe(ctx, chat, args)
+	case "/unsubscribe":
+		return b.unsubscribe(ctx, chat, args)
+	case "/ticket":
+		if len(args) > 0 && args[0] == "add" {
+			return b.addTicket(ctx, chat, args[1:])
+		}
+		if len(args) > 0 && args[0] == "remove" {
+			return b.removeTicket(ctx, chat, args[1:])
+		}
+		return "Usage: /ticket add GAME NUMBERS [+ SPECIAL NUMBERS] or /ticket remove ID", nil
+	case "/tickets":
+		return b.listTickets(ctx, chat)
+	case "/alert":
+		if len(args) > 0 && args[0] == "remove" {
+			return b.removeAlert(ctx, chat, args[1:])
+		}
+		return b.setAlert(ctx, chat, args)
+	case "/alerts":
+		return b.listAlerts(ctx, chat)
+	case "/remind":
+		if len(args) > 0 && args[0] == "off" {
+			return b.setReminders(ctx, chat, args[1:], nil)
+		}
+		return b.remind(ctx, chat, args)
+	case "/reminders":
+		return b.listReminders(ctx, chat)
+	default:
+		return "Unknown command, send /help to see what I can do.", nil
+	}
+}
+
+// start registers the chat so it gets the results
+func (b *bot) start(ctx context.Context, chat chat) (string, error) {
+	if _, err := b.chatSubscriber(ctx, chat); err != nil {
+		return "", err
+	}
+	return startText + "\n\n" + fmt.Sprintf(helpText, strings.Join(gameNames(), ", ")), nil
+}
+
+// stop forgets the chat, the subscriber is removed with its tickets, alerts and reminders
+// unless it can still be reached another way
+func (b *bot) stop(ctx context.Context, chat chat) (string, error) {
+	channel, err := b.repo.GetChannelByAddress(ctx, models.ChannelTypeTelegram, strconv.FormatInt(chat.ID, 10))
+	if errors.Is(err, sql.ErrNoRows) {
+		return "I am not sending anything to this chat, send /start to get the results.", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get telegram channel: %w", err)
+	}
+
+	channels, err := b.repo.GetSubscriberChannels(ctx, channel.SubscriberID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get subscriber channels: %w", err)
+	}
+	if len(channels) > 1 {
+		err = b.repo.DeleteSubscriberChannel(ctx, channel.ID)
+	} else {
+		err = b.repo.DeleteSubscriber(ctx, channel.SubscriberID)
+	}
+	if err != nil {
+		return "", err
+	}
+	slog.Info("Unregistered telegram chat", "chatID", chat.ID, "subscriberID", channel.SubscriberID)
+	return "Stopped, I will not send you anything anymore. Send /start to get the results again.", nil
+}
+
+func (b *bot) latest(ctx context.Context, args []string) (string, error) {
+	gameType, reply := parseGameArg(args)
+	if reply != "" {
+		return reply, nil
+	}
+
+	result, err := b.repo.GetNewestResult(ctx, string(gameType))
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Sprintf("No %s results saved yet.", gameType), nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	text := fmt.Sprintf("%s, draw %d on %s\nNumbers: %s",
+		result.GameType, result.DrawID, notifier.FormatDate(result.DrawDate), notifier.FormatNumbers(result.Results))
+	if len(result.SpecialResults) > 0 {
+		text += "\nSpecial numbers: " + notifier.FormatNumbers(result.SpecialResults)
+	}
+	return text, nil
+}
+
+func (b *bot) next(ctx context.Context, args []string) (string, error) {
+	gameType, reply := parseGameArg(args)
+	if reply != "" {
+		return reply, nil
+	}
+
+	game, err := b.repo.GetGame(ctx, string(gameType))
+	if err != nil {
+		return "", err
+	}
+	if game.TiedTo != nil {
+		game, err = b.repo.GetGame(ctx, *game.TiedTo)
+		if err != nil {
+			return "", err
+		}
+	}
+	if game.NextDrawDate == nil {
+		return fmt.Sprintf("The next %s draw is not known yet.", gameType), nil
+	}
+
+	text := fmt.Sprintf("Next %s draw: %s", gameType, notifier.FormatDate(*game.NextDrawDate))
+	if game.ClosestPrizeValue != nil {
+		text += "\nJackpot: " + notifier.FormatPLN(*game.ClosestPrizeValue)
+	}
+	return text, nil
+}
+
+func (b *bot) subscribe(ctx context.Context, chat chat, args []string) (string, error) {
+	gameType, reply := parseGameArg(args)
+	if reply != "" {
+		return reply, nil
+	}
+	subscriberID, err := b.chatSubscriber(ctx, chat)
+	if err != nil {
+		return "", err
+	}
+	if err := b.repo.Subscribe(ctx, subscriberID, gameType); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Subscribed to %s results.", gameType), nil
+}
+
+func (b *bot) unsubscribe(ctx context.Context, chat chat, args []string) (string, error) {
+	gameType, reply := parseGameArg(args)
+	if reply != "" {
+		return reply, nil
+	}
+	subscriberID, ok, err := b.knownSubscriber(ctx, chat)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return notRegisteredText, nil
+	}
+	err = b.repo.Unsubscribe(ctx, subscriberID, gameType)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Sprintf("You are not subscribed to %s.", gameType), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Unsubscribed from %s results.", gameType), nil
+}
+
+func (b *bot) addTicket(ctx context.Context, chat chat, args []string) (string, error) {
+	gameType, reply := parseGameArg(args)
+	if reply != "" {
+		return reply, nil
+	}
+	numbers, special, err := parseNumbers(args[1:])
+	if err != nil {
+		return fmt.Sprintf("Could not add the ticket: %s", err), nil
+	}
+
+	ticket := models.Ticket{
+		GameType:       gameType,
+		Numbers:        numbers,
+		SpecialNumbers: special,
+	}
+	if err := matching.ValidateTicket(ticket); err != nil {
+		return fmt.Sprintf("Could not add the ticket: %s", err), nil
+	}
+
+	ticket.SubscriberID, err = b.chatSubscriber(ctx, chat)
+	if err != nil {
+		return "", err
+	}
+	ticket, err = b.repo.CreateTicket(ctx, ticket)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Ticket %d added, I will check it after every %s draw.", ticket.ID, gameType), nil
+}
+
+func (b *bot) removeTicket(ctx context.Context, chat chat, args []string) (string, error) {
+	if len(args) != 1 {
+		return "Usage: /ticket remove ID", nil
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return "Usage: /ticket remove ID", nil
+	}
+	subscriberID, ok, err := b.knownSubscriber(ctx, chat)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return notRegisteredText, nil
+	}
+
+	tickets, err := b.repo.GetTickets(ctx, subscriberID)
+	if err != nil {
+		return "", err
+	}
+	for _, ticket := range tickets {
+		if ticket.ID == id {
+			if err := b.repo.DeleteTicket(ctx, id); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Ticket %d removed.", id), nil
+		}
+	}
+	return fmt.Sprintf("You have no ticket %d.", id), nil
+}
+
+func (b *bot) listTickets(ctx context.Context, chat chat) (string, error) {
+	subscriberID, ok, err := b.knownSubscriber(ctx, chat)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return notRegisteredText, nil
+	}
+	tickets, err := b.repo.GetTickets(ctx, subscriberID)
+	if err != nil {
+		return "", err
+	}
+	if len(tickets) == 0 {
+		return "You have no tickets, add one with /ticket add.", nil
+	}
+
+	lines := make([]string, len(tickets))
+	for idx, ticket := range tickets {
+		lines[idx] = fmt.Sprintf("%d. %s %s", ticket.ID, ticket.GameType, notifier.FormatNumbers(ticket.Numbers))
+		if len(ticket.SpecialNumbers) > 0 {
+			lines[idx] += " + " + notifier.FormatNumbers(ticket.SpecialNumbers)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func (b *bot) setAlert(ctx context.Context, chat chat, args []string) (string, error) {
+	const usage = "Usage: /alert GAME AMOUNT, e.g. /alert Lotto 20mln"
+	if len(args) != 2 {
+		return usage, nil
+	}
+	gameType, reply := parseGameArg(args)
+	if reply != "" {
+		return reply, nil
+	}
+	if game, ok := rules.Get(gameType); ok && game.TiedTo != nil {
+		return fmt.Sprintf("%s has no jackpot of its own, it is drawn together with %s.", gameType, *game.TiedTo), nil
+	}
+	threshold, err := parseAmount(args[1])
+	if err != nil {
+		return usage, nil
+	}
+
+	subscriberID, ok, err := b.knownSubscriber(ctx, chat)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return notRegisteredText, nil
+	}
+	alert, err := b.repo.SetJackpotAlert(ctx, subscriberID, gameType, threshold)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("I will tell you once the %s jackpot reaches %s.", gameType, notifier.FormatPLN(alert.Threshold)), nil
+}
+
+func (b *bot) removeAlert(ctx context.Context, chat chat, args []string) (string, error) {
+	gameType, reply := parseGameArg(args)
+	if reply != "" {
+		return reply, nil
+	}
+	subscriberID, ok, err := b.knownSubscriber(ctx, chat)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return notRegisteredText, nil
+	}
+	err = b.repo.DeleteJackpotAlert(ctx, subscriberID, gameType)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Sprintf("You have no %s jackpot alert.", gameType), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s jackpot alert removed.", gameType), nil
+}
+
+func (b *bot) listAlerts(ctx context.Context, chat chat) (string, error) {
+	subscriberID, ok, err := b.knownSubscriber(ctx, chat)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return notRegisteredText, nil
+	}
+	alerts, err := b.repo.GetJackpotAlerts(ctx, subscriberID)
+	if err != nil {
+		return "", err
+	}
+	if len(alerts) == 0 {
+		return "You have no jackpot alerts, add one with /alert.", nil
+	}
+
+	lines := make([]string, len(alerts))
+	for idx, alert := range alerts {
+		lines[idx] = fmt.Sprintf("%s: %s", alert.GameType, notifier.FormatPLN(alert.Threshold))
+		if alert.NotifiedAt != nil {
+			lines[idx] += " (sent, waiting for the jackpot to be won)"
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func (b *bot) remind(ctx context.Context, chat chat, args []string) (string, error) {
+	if len(args) < 2 {
+		return b.setReminders(ctx, chat, args, b.reminderOffsets)
+	}
+
+	offsets := make([]time.Duration, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		offset, err := time.ParseDuration(arg)
+		if err != nil || offset < time.Minute || offset > maxReminderOffset {
+			return fmt.Sprintf("Invalid offset %q, use e.g. 3h, 30m or 1h30m, up to %s.",
+				arg, notifier.FormatOffset(maxReminderOffset)), nil
+		}
+		offsets = append(offsets, offset.Truncate(time.Minute))
+	}
+	return b.setReminders(ctx, chat, args, offsets)
+}
+
+// setReminders replaces the chat's reminders for the game, no offsets turn them off
+func (b *bot) setReminders(ctx context.Context, chat chat, args []string, offsets []time.Duration) (string, error) {
+	gameType, reply := parseGameArg(args)
+	if reply != "" {
+		return reply, nil
+	}
+	subscriberID, ok, err := b.knownSubscriber(ctx, chat)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return notRegisteredText, nil
+	}
+	if err := b.repo.SetReminders(ctx, subscriberID, gameType, offsets); err != nil {
+		return "", err
+	}
+
+	if len(offsets) == 0 {
+		return fmt.Sprintf("%s reminders turned off.", gameType), nil
+	}
+	formatted := make([]string, len(offsets))
+	for idx, offset := range offsets {
+		formatted[idx] = notifier.FormatOffset(offset)
+	}
+	return fmt.Sprintf("I will remind you %s before every %s draw.", strings.Join(formatted, " and "), gameType), nil
+}
+
+func (b *bot) listReminders(ctx context.Context, chat chat) (string, error) {
+	subscriberID, ok, err := b.knownSubscriber(ctx, chat)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return notRegisteredText, nil
+	}
+	reminders, err := b.repo.GetReminders(ctx, subscriberID)
+	if err != nil {
+		return "", err
+	}
+	if len(reminders) == 0 {
+		return "You have no reminders, add one with /remind.", nil
+	}
+
+	lines := make([]string, len(reminders))
+	for idx, reminder := range reminders {
+		lines[idx] = fmt.Sprintf("%s: %s before the draw", reminder.GameType, notifier.FormatOffset(reminder.Offset()))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// knownSubscriber returns the subscriber behind a chat, ok is false when the chat is not registered.
+// Commands that only read or remove settings use it so they never sign a chat up for the results.
+func (b *bot) knownSubscriber(ctx context.Context, chat chat) (int64, bool, error) {
+	channel, err := b.repo.GetChannelByAddress(ctx, models.ChannelTypeTelegram, strconv.FormatInt(chat.ID, 10))
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get telegram channel: %w", err)
+	}
+	return channel.SubscriberID, true, nil
+}
+
+// chatSubscriber returns the subscriber behind a chat, registering the chat on first use
+func (b *bot) chatSubscriber(ctx context.Context, chat chat) (int64, error) {
+	subscriberID, ok, err := b.knownSubscriber(ctx, chat)
+	if err != nil || ok {
+		return subscriberID, err
+	}
+
+	address := strconv.FormatInt(chat.ID, 10)
+	subscriber, err := b.repo.CreateSubscriber(ctx, models.Subscriber{Name: chatName(chat)})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create subscriber: %w", err)
+	}
+	_, err = b.repo.AddSubscriberChannel(ctx, models.SubscriberChannel{
+		SubscriberID: subscriber.ID,
+		Type:         models.ChannelTypeTelegram,
+		Address:      address,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to add telegram channel: %w", err)
+	}
+	slog.Info("Registered telegram chat", "chatID", chat.ID, "subscriberID", subscriber.ID)
+	return subscriber.ID, nil
+}
+
+func chatName(chat chat) string {
+	switch {
+	case chat.Title != "":
+		return chat.Title
+	case chat.Username != "":
+		return "@" + chat.Username
+	case chat.FirstName != "":
+		return chat.FirstName
+	default:
+		return "telegram " + strconv.FormatInt(chat.ID, 10)
+	}
+}
+
+func gameNames() []string {
+	games := rules.All()
+	names := make([]string, len(games))
+	for idx, game := range games {
+		names[idx] = string(game.GameType)
+	}
+	return names
+}
+
+// parseGameArg reads the game from the first argument, on failure it returns the reply explaining why
+func parseGameArg(args []string) (models.GameType, string) {
+	if len(args) == 0 {
+		return "", "Which game? One of: " + strings.Join(gameNames(), ", ")
+	}
+	for _, game := range rules.All() {
+		if strings.EqualFold(string(game.GameType), args[0]) {
+			return game.GameType, ""
+		}
+	}
+	return "", fmt.Sprintf("Unknown game %q, use one of: %s", args[0], strings.Join(gameNames(), ", "))
+}
+
+// parseAmount reads an amount in PLN, "mln" and "k" suffixes are allowed, e.g. "20mln" or "500k"
+func parseAmount(arg string) (float64, error) {
+	arg = strings.ToLower(strings.ReplaceAll(arg, ",", "."))
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(arg, "mln"):
+		arg, multiplier = strings.TrimSuffix(arg, "mln"), 1_000_000
+	case strings.HasSuffix(arg, "k"):
+		arg, multiplier = strings.TrimSuffix(arg, "k"), 1_000
+	}
+	amount, err := strconv.ParseFloat(arg, 64)
+	if err != nil || !(amount > 0) || math.IsInf(amount, 0) {
+		return 0, fmt.Errorf("invalid amount %q", arg)
+	}
+	return amount * multiplier, nil
+}
+
+// parseNumbers reads "1 2 3 + 4 5" into main and special numbers, commas are allowed as separators
+func parseNumbers(args []string) ([]int, []int, error) {
+	joined := strings.ReplaceAll(strings.Join(args, " "), ",", " ")
+	mainPart, specialPart, _ := strings.Cut(joined, "+")
+
+	parse := func(part string) ([]int, error) {
+		var numbers []int
+		for _, field := range strings.Fields(part) {
+			num, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a number", field)
+			}
+			numbers = append(numbers, num)
+		}
+		return numbers, nil
+	}
+
+	numbers, err := parse(mainPart)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(numbers) == 0 {
+		return nil, nil, errors.New("no numbers given")
+	}
+	special, err := parse(specialPart)
+	if err != nil {
+		return nil, nil, err
+	}
+	return numbers, special, nil
+}
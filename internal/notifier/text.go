@@ -0,0 +1,59 @@
+package notifier
+
+import (
+	"fmt"
+	"strings"
+
+	"lotto-notifications/internal/matching"
+	"lotto-notifications/internal/models"
+)
+
+// ResultsText renders the results and the checked tickets as a plain-text message
+// for channels without rich formatting
+func ResultsText(game models.Game, results []models.Result, matches []matching.Match) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s draw results\n", game.GameType)
+
+	for _, result := range results {
+		fmt.Fprintf(&sb, "\n%s, draw %d on %s\n", result.GameType, result.DrawID, FormatDate(result.DrawDate))
+		fmt.Fprintf(&sb, "Numbers: %s\n", FormatNumbers(result.Results))
+		if len(result.SpecialResults) > 0 {
+			fmt.Fprintf(&sb, "Special numbers: %s\n", FormatNumbers(result.SpecialResults))
+		}
+	}
+
+	if len(matches) > 0 {
+		sb.WriteString("\nYour tickets:\n")
+		for _, match := range matches {
+			sb.WriteString("- " + MatchText(match) + "\n")
+		}
+	}
+
+	if game.NextDrawDate != nil {
+		fmt.Fprintf(&sb, "\nNext draw: %s", FormatDate(*game.NextDrawDate))
+	}
+	if game.ClosestPrizeValue != nil {
+		fmt.Fprintf(&sb, "\nNext jackpot: %s", FormatPLN(*game.ClosestPrizeValue))
+	}
+
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// MatchText describes a checked ticket, e.g. "Lotto 1, 2, 3, 4, 5, 6: matched 4 — tier III paid 215.40 PLN this draw"
+func MatchText(match matching.Match) string {
+	text := fmt.Sprintf("%s %s", match.Ticket.GameType, FormatNumbers(match.Ticket.Numbers))
+	if len(match.Ticket.SpecialNumbers) > 0 {
+		text += " + " + FormatNumbers(match.Ticket.SpecialNumbers)
+	}
+	text += fmt.Sprintf(": matched %d", match.MainHits)
+	if match.SpecialHits > 0 {
+		text += fmt.Sprintf("+%d", match.SpecialHits)
+	}
+	if !match.Won() {
+		return text + ", no prize"
+	}
+	if match.Prize != nil {
+		return text + fmt.Sprintf(" — tier %s paid %s this draw", match.Tier.Name(), FormatPLN(match.Prize.Amount))
+	}
+	return text + ", tier " + match.Tier.Name()
+}
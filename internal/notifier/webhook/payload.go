@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"time"
+
+	"lotto-notifications/internal/models"
+)
+
+// SchemaVersion is bumped whenever the payload changes in a way receivers have to handle
+const SchemaVersion = 1
+
+const eventDrawResults = "draw.results"
+
+type payload struct {
+	SchemaVersion int             `json:"schema_version"`
+	DeliveryID    string          `json:"delivery_id"`
+	Event         string          `json:"event"`
+	SentAt        time.Time       `json:"sent_at"`
+	Game          gamePayload     `json:"game"`
+	Results       []resultPayload `json:"results"`
+}
+
+type gamePayload struct {
+	Type              models.GameType `json:"type"`
+	NextDrawDate      *time.Time      `json:"next_draw_date"`
+	ClosestPrizeValue *float64        `json:"closest_prize_value"`
+	ClosestPrizePool  *string         `json:"closest_prize_pool"`
+	CouponPrice       *string         `json:"coupon_price"`
+}
+
+type resultPayload struct {
+	DrawID         uint            `json:"draw_id"`
+	GameType       models.GameType `json:"game_type"`
+	DrawDate       time.Time       `json:"draw_date"`
+	Results        []int           `json:"results"`
+	SpecialResults []int           `json:"special_results"`
+}
+
+func newPayload(deliveryID string, game models.Game, results []models.Result) payload {
+	p := payload{
+		SchemaVersion: SchemaVersion,
+		DeliveryID:    deliveryID,
+		Event:         eventDrawResults,
+		SentAt:        time.Now().UTC(),
+		Game: gamePayload{
+			Type:              game.GameType,
+			NextDrawDate:      game.NextDrawDate,
+			ClosestPrizeValue: game.ClosestPrizeValue,
+			ClosestPrizePool:  game.ClosestPrizePool,
+			CouponPrice:       game.CouponPrice,
+		},
+		Results: make([]resultPayload, len(results)),
+	}
+	for idx, result := range results {
+		special := result.SpecialResults
+		if special == nil {
+			special = []int{}
+		}
+		p.Results[idx] = resultPayload{
+			DrawID:         result.DrawID,
+			GameType:       result.GameType,
+			DrawDate:       result.DrawDate,
+			Results:        result.Results,
+			SpecialResults: special,
+		}
+	}
+	return p
+}
@@ -0,0 +1,176 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/notifier"
+	"lotto-notifications/internal/repository"
+)
+
+const (
+	SignatureHeader  = "X-Signature"
+	DeliveryIDHeader = "X-Delivery-ID"
+)
+
+type webhookNotifier struct {
+	httpClient *http.Client
+	repo       repository.Repository
+}
+
+// New returns a channel that posts the results to every active webhook
+func New(repo repository.Repository) notifier.Notifier {
+	return &webhookNotifier{
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		repo: repo,
+	}
+}
+
+func (n *webhookNotifier) Name() string {
+	return "webhook"
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, game models.Game, results []models.Result) error {
+	webhooks, err := n.repo.GetWebhooks(ctx, true)
+	if err != nil {
+		return fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	var errs []error
+	for _, webhook := range webhooks {
+		deliveryID, err := newDeliveryID()
+		if err != nil {
+			return err
+		}
+		if err := n.deliver(ctx, webhook, deliveryID, game, results); err != nil {
+			errs = append(errs, fmt.Errorf("webhook %d: %w", webhook.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *webhookNotifier) Targets(ctx context.Context, game models.Game, results []models.Result) ([]string, error) {
+	webhooks, err := n.repo.GetWebhooks(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	targets := make([]string, len(webhooks))
+	for idx, webhook := range webhooks {
+		targets[idx] = strconv.FormatInt(webhook.ID, 10)
+	}
+	return targets, nil
+}
+
+// NotifyTarget delivers to a single webhook, a webhook that was deleted or deactivated since is skipped
+func (n *webhookNotifier) NotifyTarget(
+	ctx context.Context, target notifier.Target, game models.Game, results []models.Result,
+) error {
+	webhooks, err := n.repo.GetWebhooks(ctx, true)
+	if err != nil {
+		return fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	idx := slices.IndexFunc(webhooks, func(webhook models.Webhook) bool {
+		return strconv.FormatInt(webhook.ID, 10) == target.Key
+	})
+	if idx < 0 {
+		return nil
+	}
+	return n.deliver(ctx, webhooks[idx], deliveryID(target), game, results)
+}
+
+// deliver posts the payload to a single webhook and records the attempt
+func (n *webhookNotifier) deliver(
+	ctx context.Context, webhook models.Webhook, deliveryID string, game models.Game, results []models.Result,
+) error {
+	body, err := json.Marshal(newPayload(deliveryID, game, results))
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	start := time.Now()
+	statusCode, err := n.post(ctx, webhook, deliveryID, body)
+
+	delivery := models.WebhookDelivery{
+		WebhookID:  webhook.ID,
+		DeliveryID: deliveryID,
+		DurationMS: time.Since(start).Milliseconds(),
+	}
+	if statusCode != 0 {
+		delivery.StatusCode = &statusCode
+	}
+	if err != nil {
+		message := err.Error()
+		delivery.Error = &message
+	}
+	if recordErr := n.repo.InsertWebhookDelivery(ctx, delivery); recordErr != nil {
+		slog.Error("Failed to record webhook delivery", "webhookID", webhook.ID, "error", recordErr)
+	}
+
+	return err
+}
+
+func (n *webhookNotifier) post(ctx context.Context, webhook models.Webhook, deliveryID string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(DeliveryIDHeader, deliveryID)
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, body))
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign computes the X-Signature header value, "sha256=" followed by the hex HMAC-SHA256
+// of the raw request body keyed with the webhook secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature produced by Sign in constant time, for use by receivers
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+// deliveryID derives the delivery ID from the outbox message and the webhook,
+// so every retry of a delivery carries the same ID and receivers can drop duplicates
+func deliveryID(target notifier.Target) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d/%s", target.MessageID, target.Key)))
+	return hex.EncodeToString(sum[:16])
+}
+
+func newDeliveryID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate delivery id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,141 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/notifier"
+	"lotto-notifications/internal/repository/repositorytest"
+)
+
+// receivedDelivery is a request received by the fake webhook receiver
+type receivedDelivery struct {
+	DeliveryID string
+	Signature  string
+	Body       []byte
+}
+
+// newReceiver answers the first failures requests with 503 and the rest with 204
+func newReceiver(t *testing.T, failures int) (*httptest.Server, func() []receivedDelivery) {
+	t.Helper()
+	var mu sync.Mutex
+	var received []receivedDelivery
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, receivedDelivery{
+			DeliveryID: r.Header.Get(DeliveryIDHeader),
+			Signature:  r.Header.Get(SignatureHeader),
+			Body:       body,
+		})
+		failed := len(received) <= failures
+		mu.Unlock()
+
+		if failed {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []receivedDelivery {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]receivedDelivery(nil), received...)
+	}
+}
+
+func TestNotifyTargetReusesDeliveryID(t *testing.T) {
+	ctx := context.Background()
+	repo := repositorytest.New(t)
+	server, received := newReceiver(t, 2)
+	webhook, err := repo.CreateWebhook(ctx, models.Webhook{URL: server.URL, Secret: "secret", Active: true})
+	if err != nil {
+		t.Fatalf("CreateWebhook() error = %v", err)
+	}
+	n := New(repo).(notifier.FanoutNotifier)
+
+	game := models.Game{GameType: models.GameTypeLotto}
+	results := []models.Result{{DrawID: 7000, GameType: models.GameTypeLotto, Results: models.IntSlice{1, 2, 3, 4, 5, 6}}}
+	target := notifier.Target{Key: strconv.FormatInt(webhook.ID, 10), MessageID: 42}
+
+	targets, err := n.Targets(ctx, game, results)
+	if err != nil || len(targets) != 1 || targets[0] != target.Key {
+		t.Fatalf("Targets() = %v, %v, want [%s]", targets, err, target.Key)
+	}
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		err := n.NotifyTarget(ctx, target, game, results)
+		if attempt < 3 && err == nil {
+			t.Fatalf("attempt %d: NotifyTarget() succeeded, want the receiver's error", attempt)
+		}
+		if attempt == 3 && err != nil {
+			t.Fatalf("attempt %d: NotifyTarget() error = %v", attempt, err)
+		}
+	}
+
+	got := received()
+	if len(got) != 3 {
+		t.Fatalf("received %d deliveries, want 3", len(got))
+	}
+	want := deliveryID(target)
+	for idx, delivery := range got {
+		if delivery.DeliveryID != want {
+			t.Errorf("attempt %d: delivery id = %q, want %q", idx+1, delivery.DeliveryID, want)
+		}
+		if !Verify("secret", delivery.Body, delivery.Signature) {
+			t.Errorf("attempt %d: signature does not verify", idx+1)
+		}
+	}
+
+	deliveries, err := repo.GetWebhookDeliveries(ctx, webhook.ID)
+	if err != nil {
+		t.Fatalf("GetWebhookDeliveries() error = %v", err)
+	}
+	if len(deliveries) != 3 {
+		t.Fatalf("recorded %d deliveries, want 3", len(deliveries))
+	}
+	for _, delivery := range deliveries {
+		if delivery.DeliveryID != want {
+			t.Errorf("recorded delivery id = %q, want %q", delivery.DeliveryID, want)
+		}
+	}
+}
+
+func TestDeliveryID(t *testing.T) {
+	target := notifier.Target{Key: "1", MessageID: 42}
+	if got := deliveryID(target); len(got) != 32 {
+		t.Errorf("deliveryID() = %q, want 32 hex characters", got)
+	}
+	for _, other := range []notifier.Target{{Key: "2", MessageID: 42}, {Key: "1", MessageID: 43}} {
+		if deliveryID(other) == deliveryID(target) {
+			t.Errorf("deliveryID(%+v) = deliveryID(%+v)", other, target)
+		}
+	}
+}
+
+func TestNotifyTargetSkipsInactiveWebhook(t *testing.T) {
+	ctx := context.Background()
+	repo := repositorytest.New(t)
+	server, received := newReceiver(t, 0)
+	webhook, err := repo.CreateWebhook(ctx, models.Webhook{URL: server.URL, Secret: "secret"})
+	if err != nil {
+		t.Fatalf("CreateWebhook() error = %v", err)
+	}
+	n := New(repo).(notifier.FanoutNotifier)
+
+	target := notifier.Target{Key: strconv.FormatInt(webhook.ID, 10), MessageID: 42}
+	if err := n.NotifyTarget(ctx, target, models.Game{GameType: models.GameTypeLotto}, nil); err != nil {
+		t.Fatalf("NotifyTarget() error = %v", err)
+	}
+	if got := received(); len(got) != 0 {
+		t.Errorf("received %d deliveries, want none", len(got))
+	}
+}
@@ -0,0 +1,233 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/notifier"
+	"lotto-notifications/internal/repository"
+)
+
+const (
+	batchSize  = 50
+	maxBackoff = 6 * time.Hour
+
+	defaultPollInterval = 10 * time.Second
+	defaultMaxAttempts  = 8
+	defaultRetryBackoff = time.Minute
+)
+
+// Relay drains the outbox, delivering every queued message to its channel at least once
+type Relay interface {
+	Run(ctx context.Context)
+}
+
+type Config struct {
+	PollInterval time.Duration
+	// MaxAttempts is the number of failed deliveries after which a message is dead
+	MaxAttempts int
+	// RetryBackoff is the delay after the first failure, it doubles with every attempt
+	RetryBackoff time.Duration
+}
+
+type relay struct {
+	repo       repository.Repository
+	dispatcher notifier.Dispatcher
+	cfg        Config
+}
+
+// NewRelay returns a relay, settings that are not positive fall back to their defaults
+func NewRelay(repo repository.Repository, dispatcher notifier.Dispatcher, cfg Config) Relay {
+	if cfg.PollInterval <= 0 {
+		slog.Warn("Invalid outbox poll interval, using the default", "pollInterval", cfg.PollInterval)
+		cfg.PollInterval = defaultPollInterval
+	}
+	if cfg.MaxAttempts <= 0 {
+		slog.Warn("Invalid outbox max attempts, using the default", "maxAttempts", cfg.MaxAttempts)
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	if cfg.RetryBackoff <= 0 {
+		slog.Warn("Invalid outbox retry backoff, using the default", "retryBackoff", cfg.RetryBackoff)
+		cfg.RetryBackoff = defaultRetryBackoff
+	}
+	return &relay{
+		repo:       repo,
+		dispatcher: dispatcher,
+		cfg:        cfg,
+	}
+}
+
+// Run polls for due messages until the context is cancelled
+func (r *relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		r.drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain delivers due messages batch by batch until none are left
+func (r *relay) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		messages, err := r.repo.GetDueOutboxMessages(ctx, time.Now(), batchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.Error("Failed to get outbox messages", "error", err)
+			}
+			return
+		}
+
+		for _, msg := range messages {
+			if ctx.Err() != nil {
+				return
+			}
+			r.deliver(ctx, msg)
+		}
+
+		if len(messages) < batchSize {
+			return
+		}
+	}
+}
+
+func (r *relay) deliver(ctx context.Context, msg models.OutboxMessage) {
+	channel, ok := r.dispatcher.Channel(msg.Channel)
+	if !ok {
+		r.fail(ctx, msg, fmt.Errorf("%w: %s", notifier.ErrUnknownChannel, msg.Channel))
+		return
+	}
+	if fanout, ok := channel.(notifier.FanoutNotifier); ok && msg.Target == nil {
+		r.expand(ctx, msg, fanout)
+		return
+	}
+
+	if err := r.notify(ctx, msg, channel); err != nil {
+		r.fail(ctx, msg, err)
+		return
+	}
+	if err := r.repo.MarkOutboxSent(ctx, msg.ID); err != nil {
+		// the message stays pending and is delivered again, hence at-least-once
+		slog.Error("Failed to mark outbox message as sent", "id", msg.ID, "error", err)
+		return
+	}
+	slog.Debug("Delivered outbox message", "id", msg.ID, "channel", msg.Channel, "game", msg.GameType)
+}
+
+// expand splits a message for a channel with many recipients into one message per recipient
+// and delivers those right away, each of them is retried on its own from then on
+func (r *relay) expand(ctx context.Context, msg models.OutboxMessage, channel notifier.FanoutNotifier) {
+	game, results, err := r.load(ctx, msg)
+	if err != nil {
+		r.fail(ctx, msg, err)
+		return
+	}
+	targets, err := channel.Targets(ctx, game, results)
+	if err != nil {
+		r.fail(ctx, msg, fmt.Errorf("failed to get targets: %w", err))
+		return
+	}
+	messages, err := r.repo.ExpandOutboxMessage(ctx, msg.ID, targets)
+	if err != nil {
+		// the message stays pending and is expanded again on the next poll
+		slog.Error("Failed to expand outbox message", "id", msg.ID, "error", err)
+		return
+	}
+	slog.Debug("Expanded outbox message", "id", msg.ID, "channel", msg.Channel, "targets", len(messages))
+
+	for _, message := range messages {
+		if ctx.Err() != nil {
+			return
+		}
+		r.deliver(ctx, message)
+	}
+}
+
+// fail records a failed attempt, the message is retried after a backoff or moved to dead letters
+func (r *relay) fail(ctx context.Context, msg models.OutboxMessage, err error) {
+	if ctx.Err() != nil {
+		// interrupted by shutdown, not counted as an attempt
+		return
+	}
+
+	attempts := msg.Attempts + 1
+	dead := attempts >= r.cfg.MaxAttempts || errors.Is(err, notifier.ErrUnknownChannel)
+	nextAttemptAt := time.Now().Add(r.backoff(attempts))
+
+	if dead {
+		slog.Error("Outbox message moved to dead letters",
+			"id", msg.ID,
+			"channel", msg.Channel,
+			"target", msg.Target,
+			"game", msg.GameType,
+			"attempts", attempts,
+			"error", err,
+		)
+	} else {
+		slog.Warn("Failed to deliver outbox message, retrying later",
+			"id", msg.ID,
+			"channel", msg.Channel,
+			"target", msg.Target,
+			"game", msg.GameType,
+			"attempts", attempts,
+			"nextAttemptAt", nextAttemptAt,
+			"error", err,
+		)
+	}
+
+	if err := r.repo.MarkOutboxFailed(ctx, msg.ID, err.Error(), nextAttemptAt, dead); err != nil {
+		slog.Error("Failed to mark outbox message as failed", "id", msg.ID, "error", err)
+	}
+}
+
+// notify delivers a message to the whole channel, or to its target only when it has one
+func (r *relay) notify(ctx context.Context, msg models.OutboxMessage, channel notifier.Notifier) error {
+	game, results, err := r.load(ctx, msg)
+	if err != nil {
+		return err
+	}
+	if msg.Target == nil || msg.ParentID == nil {
+		return channel.Notify(ctx, game, results)
+	}
+
+	fanout, ok := channel.(notifier.FanoutNotifier)
+	if !ok {
+		return fmt.Errorf("%w: %s does not deliver to single targets", notifier.ErrUnknownChannel, msg.Channel)
+	}
+	target := notifier.Target{Key: *msg.Target, MessageID: *msg.ParentID}
+	return fanout.NotifyTarget(ctx, target, game, results)
+}
+
+// load decodes the results in the payload and gets their game
+func (r *relay) load(ctx context.Context, msg models.OutboxMessage) (models.Game, []models.Result, error) {
+	var results []models.Result
+	if err := json.Unmarshal([]byte(msg.Payload), &results); err != nil {
+		return models.Game{}, nil, fmt.Errorf("failed to decode payload: %w", err)
+	}
+
+	game, err := r.repo.GetGame(ctx, string(msg.GameType))
+	if err != nil {
+		return models.Game{}, nil, fmt.Errorf("failed to get game: %w", err)
+	}
+	return game, results, nil
+}
+
+// backoff returns the delay before the next attempt, doubling with every failed attempt
+func (r *relay) backoff(attempts int) time.Duration {
+	delay := r.cfg.RetryBackoff
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
@@ -0,0 +1,249 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/notifier"
+	"lotto-notifications/internal/repository"
+	"lotto-notifications/internal/repository/repositorytest"
+)
+
+// fakeChannel is a channel the relay delivers to target by target. It counts the deliveries
+// and fails a target until it was attempted as many times as failing says.
+type fakeChannel struct {
+	name    string
+	targets []string
+	failing map[string]int
+
+	mu        sync.Mutex
+	calls     map[string]int
+	messageID map[string][]int64
+}
+
+func newFakeChannel(name string, targets ...string) *fakeChannel {
+	return &fakeChannel{
+		name:      name,
+		targets:   targets,
+		failing:   map[string]int{},
+		calls:     map[string]int{},
+		messageID: map[string][]int64{},
+	}
+}
+
+func (c *fakeChannel) Name() string {
+	return c.name
+}
+
+func (c *fakeChannel) Notify(ctx context.Context, game models.Game, results []models.Result) error {
+	return c.NotifyTarget(ctx, notifier.Target{Key: "*"}, game, results)
+}
+
+func (c *fakeChannel) Targets(ctx context.Context, game models.Game, results []models.Result) ([]string, error) {
+	return c.targets, nil
+}
+
+func (c *fakeChannel) NotifyTarget(
+	ctx context.Context, target notifier.Target, game models.Game, results []models.Result,
+) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls[target.Key]++
+	c.messageID[target.Key] = append(c.messageID[target.Key], target.MessageID)
+	if c.calls[target.Key] <= c.failing[target.Key] {
+		return errors.New("recipient unavailable")
+	}
+	return nil
+}
+
+func (c *fakeChannel) calledWith(key string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls[key]
+}
+
+// plainChannel hides the fan-out methods of a fakeChannel
+type plainChannel struct {
+	fake *fakeChannel
+}
+
+func (c plainChannel) Name() string {
+	return c.fake.Name()
+}
+
+func (c plainChannel) Notify(ctx context.Context, game models.Game, results []models.Result) error {
+	return c.fake.Notify(ctx, game, results)
+}
+
+// enqueue saves a Lotto result, queueing one outbox message per channel
+func enqueue(t *testing.T, repo repository.Repository, channels ...string) {
+	t.Helper()
+	drawDate := time.Date(2026, time.October, 17, 20, 0, 0, 0, time.UTC)
+	draw := models.Draw{
+		DrawID:   7000,
+		GameType: models.GameTypeLotto,
+		DrawDate: drawDate,
+		Results: []models.Result{
+			{DrawID: 7000, GameType: models.GameTypeLotto, DrawDate: drawDate, Results: models.IntSlice{1, 2, 3, 4, 5, 6}},
+		},
+	}
+	if _, err := repo.SaveDraws(context.Background(), models.GameTypeLotto, []models.Draw{draw}, channels); err != nil {
+		t.Fatalf("SaveDraws() error = %v", err)
+	}
+}
+
+// statuses returns the status of every outbox message keyed by "channel/target", the target is empty
+// for a message to the whole channel
+func statuses(t *testing.T, repo repository.Repository) map[string]models.OutboxStatus {
+	t.Helper()
+	got := map[string]models.OutboxStatus{}
+	for _, status := range []models.OutboxStatus{
+		models.OutboxStatusPending, models.OutboxStatusSent, models.OutboxStatusDead, models.OutboxStatusExpanded,
+	} {
+		messages, err := repo.GetOutboxMessages(context.Background(), status)
+		if err != nil {
+			t.Fatalf("GetOutboxMessages() error = %v", err)
+		}
+		for _, msg := range messages {
+			key := msg.Channel + "/"
+			if msg.Target != nil {
+				key += *msg.Target
+			}
+			got[key] = msg.Status
+		}
+	}
+	return got
+}
+
+func newTestRelay(repo repository.Repository, channels ...notifier.Notifier) *relay {
+	return NewRelay(repo, notifier.NewDispatcher(channels...), Config{
+		PollInterval: time.Hour,
+		MaxAttempts:  3,
+		RetryBackoff: time.Millisecond,
+	}).(*relay)
+}
+
+// drainUntilSettled drains the outbox until no message is pending any more
+func drainUntilSettled(t *testing.T, r *relay) {
+	t.Helper()
+	for range 10 {
+		r.drain(context.Background())
+		pending, err := r.repo.GetOutboxMessages(context.Background(), models.OutboxStatusPending)
+		if err != nil {
+			t.Fatalf("GetOutboxMessages() error = %v", err)
+		}
+		if len(pending) == 0 {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("outbox still has pending messages")
+}
+
+func TestRelayRetriesFailedTargetAlone(t *testing.T) {
+	repo := repositorytest.New(t)
+	email := newFakeChannel("email", "1", "2", "3")
+	email.failing["2"] = 1
+	log := newFakeChannel("log")
+	r := newTestRelay(repo, email, plainChannel{log})
+	enqueue(t, repo, "email", "log")
+
+	drainUntilSettled(t, r)
+
+	for key, want := range map[string]int{"1": 1, "2": 2, "3": 1} {
+		if got := email.calledWith(key); got != want {
+			t.Errorf("email target %s notified %d times, want %d", key, got, want)
+		}
+	}
+	if got := log.calledWith("*"); got != 1 {
+		t.Errorf("log notified %d times, want 1", got)
+	}
+
+	want := map[string]models.OutboxStatus{
+		"email/":  models.OutboxStatusExpanded,
+		"email/1": models.OutboxStatusSent,
+		"email/2": models.OutboxStatusSent,
+		"email/3": models.OutboxStatusSent,
+		"log/":    models.OutboxStatusSent,
+	}
+	got := statuses(t, repo)
+	for key, status := range want {
+		if got[key] != status {
+			t.Errorf("message %s is %q, want %q", key, got[key], status)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("outbox holds %v, want %v", got, want)
+	}
+}
+
+func TestRelayPassesStableMessageID(t *testing.T) {
+	repo := repositorytest.New(t)
+	webhook := newFakeChannel("webhook", "1", "2")
+	webhook.failing["1"] = 2
+	r := newTestRelay(repo, webhook)
+	enqueue(t, repo, "webhook")
+
+	drainUntilSettled(t, r)
+
+	parents, err := repo.GetOutboxMessages(context.Background(), models.OutboxStatusExpanded)
+	if err != nil || len(parents) != 1 {
+		t.Fatalf("GetOutboxMessages() = %v, %v, want one expanded message", parents, err)
+	}
+	for key, attempts := range map[string]int{"1": 3, "2": 1} {
+		ids := webhook.messageID[key]
+		if len(ids) != attempts {
+			t.Fatalf("target %s attempted %d times, want %d", key, len(ids), attempts)
+		}
+		if slices.ContainsFunc(ids, func(id int64) bool { return id != parents[0].ID }) {
+			t.Errorf("target %s got message ids %v, want %d on every attempt", key, ids, parents[0].ID)
+		}
+	}
+}
+
+func TestRelayDeadTargetKeepsOthersSent(t *testing.T) {
+	repo := repositorytest.New(t)
+	telegram := newFakeChannel("telegram", "1", "2")
+	telegram.failing["2"] = 10
+	r := newTestRelay(repo, telegram)
+	enqueue(t, repo, "telegram", "gone")
+
+	drainUntilSettled(t, r)
+
+	if got := telegram.calledWith("1"); got != 1 {
+		t.Errorf("target 1 notified %d times, want 1", got)
+	}
+	if got := telegram.calledWith("2"); got != 3 {
+		t.Errorf("target 2 notified %d times, want 3", got)
+	}
+	want := map[string]models.OutboxStatus{
+		"telegram/":  models.OutboxStatusExpanded,
+		"telegram/1": models.OutboxStatusSent,
+		"telegram/2": models.OutboxStatusDead,
+		"gone/":      models.OutboxStatusDead,
+	}
+	got := statuses(t, repo)
+	for key, status := range want {
+		if got[key] != status {
+			t.Errorf("message %s is %q, want %q", key, got[key], status)
+		}
+	}
+}
+
+func TestNewRelayDefaults(t *testing.T) {
+	r := NewRelay(nil, notifier.NewDispatcher(), Config{PollInterval: -time.Second}).(*relay)
+
+	want := Config{
+		PollInterval: defaultPollInterval,
+		MaxAttempts:  defaultMaxAttempts,
+		RetryBackoff: defaultRetryBackoff,
+	}
+	if r.cfg != want {
+		t.Errorf("cfg = %+v, want %+v", r.cfg, want)
+	}
+}
@@ -0,0 +1,203 @@
+package prizes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"lotto-notifications/internal/matching"
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/notifier"
+	"lotto-notifications/internal/repository"
+	"lotto-notifications/internal/service"
+)
+
+const (
+	pollInterval = time.Minute
+
+	retryBackoff    = 15 * time.Minute
+	maxRetryBackoff = 2 * time.Hour
+	// expireAfter is how long the prizes of a draw are waited for before giving up
+	expireAfter = 3 * 24 * time.Hour
+)
+
+// Poller fetches the prizes of the saved results once they are published
+// and tells the winners what their tickets paid.
+// The checks are persisted with the results so they survive restarts.
+type Poller interface {
+	Run(ctx context.Context)
+}
+
+type poller struct {
+	service    service.Service
+	repo       repository.Repository
+	messengers map[models.ChannelType]notifier.Messenger
+}
+
+func NewPoller(service service.Service, repo repository.Repository, messengers ...notifier.Messenger) Poller {
+	byType := make(map[models.ChannelType]notifier.Messenger, len(messengers))
+	for _, messenger := range messengers {
+		byType[messenger.ChannelType()] = messenger
+	}
+	return &poller{
+		service:    service,
+		repo:       repo,
+		messengers: byType,
+	}
+}
+
+// Run checks the due draws until the context is cancelled
+func (p *poller) Run(ctx context.Context) {
+	for {
+		if err := p.checkDue(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("Failed to check prizes", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+func (p *poller) checkDue(ctx context.Context) error {
+	due, err := p.repo.GetDuePrizeChecks(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to get due prize checks: %w", err)
+	}
+
+	for _, check := range due {
+		err := p.check(ctx, check)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err == nil {
+			if err := p.repo.MarkPrizeCheckDone(ctx, check.GameType, check.DrawID); err != nil {
+				slog.Error("Failed to mark prize check as done", "game", check.GameType, "drawID", check.DrawID, "error", err)
+			}
+			continue
+		}
+
+		nextCheckAt := time.Now().Add(backoff(check.Attempts + 1))
+		expired := nextCheckAt.Sub(check.CreatedAt) > expireAfter
+		if expired {
+			slog.Warn("Giving up on prizes", "game", check.GameType, "drawID", check.DrawID, "error", err)
+		} else if !errors.Is(err, errNotPublished) {
+			slog.Error("Failed to check prizes",
+				"game", check.GameType,
+				"drawID", check.DrawID,
+				"attempts", check.Attempts+1,
+				"nextCheckAt", nextCheckAt,
+				"error", err,
+			)
+		}
+		if err := p.repo.MarkPrizeCheckFailed(ctx, check.GameType, check.DrawID, nextCheckAt, expired); err != nil {
+			slog.Error("Failed to mark prize check as failed", "game", check.GameType, "drawID", check.DrawID, "error", err)
+		}
+	}
+	return nil
+}
+
+var errNotPublished = errors.New("prizes not published yet")
+
+// check stores the prizes of the draw and sends the payouts, the subscribers who were
+// told before are skipped so a retried check never messages them twice
+func (p *poller) check(ctx context.Context, check models.PrizeCheck) error {
+	result, err := p.repo.GetResult(ctx, check.GameType, check.DrawID)
+	if err != nil {
+		return fmt.Errorf("failed to get result: %w", err)
+	}
+	if err := p.service.UpdatePrizes(ctx, []models.Result{result}); err != nil {
+		return err
+	}
+	prizes, err := p.repo.GetPrizes(ctx, check.GameType, check.DrawID)
+	if err != nil {
+		return fmt.Errorf("failed to get prizes: %w", err)
+	}
+	if len(prizes) == 0 {
+		return errNotPublished
+	}
+
+	matches, err := notifier.MatchTickets(ctx, p.repo, []models.Result{result})
+	if err != nil {
+		return err
+	}
+	notified, err := p.repo.GetPayoutSubscribers(ctx, check.GameType, check.DrawID)
+	if err != nil {
+		return fmt.Errorf("failed to get payout subscribers: %w", err)
+	}
+
+	var errs []error
+	for subscriberID, subscriberMatches := range matches {
+		if slices.Contains(notified, subscriberID) {
+			continue
+		}
+		won := slices.DeleteFunc(subscriberMatches, func(match matching.Match) bool { return match.Prize == nil })
+		if len(won) == 0 {
+			continue
+		}
+		if err := p.sendPayout(ctx, subscriberID, result, won); err != nil {
+			errs = append(errs, fmt.Errorf("subscriber %d: %w", subscriberID, err))
+			continue
+		}
+		if err := p.repo.MarkPayoutNotified(ctx, check.GameType, check.DrawID, subscriberID); err != nil {
+			errs = append(errs, fmt.Errorf("failed to mark payout notified: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (p *poller) sendPayout(ctx context.Context, subscriberID int64, result models.Result, matches []matching.Match) error {
+	channels, err := p.repo.GetSubscriberChannels(ctx, subscriberID)
+	if err != nil {
+		return fmt.Errorf("failed to get subscriber channels: %w", err)
+	}
+
+	msg := payoutMessage(result, matches)
+	var errs []error
+	sent := 0
+	for _, channel := range channels {
+		messenger, ok := p.messengers[channel.Type]
+		if !ok {
+			continue
+		}
+		if err := messenger.Send(ctx, channel, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s channel %d: %w", channel.Type, channel.ID, err))
+			continue
+		}
+		sent++
+	}
+	if sent == 0 && len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	slog.Info("Sent payout", "game", result.GameType, "drawID", result.DrawID, "subscriberID", subscriberID, "channels", sent)
+	return nil
+}
+
+// backoff returns the delay before checking a draw again, doubling with every check
+func backoff(attempts int) time.Duration {
+	delay := retryBackoff
+	for i := 1; i < attempts && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryBackoff)
+}
+
+func payoutMessage(result models.Result, matches []matching.Match) notifier.Message {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "The prizes of the %s draw %d on %s were published.\n",
+		result.GameType, result.DrawID, notifier.FormatDate(result.DrawDate))
+	for _, match := range matches {
+		sb.WriteString("\n- " + notifier.MatchText(match))
+	}
+	return notifier.Message{
+		Title: fmt.Sprintf("%s draw %d prizes", result.GameType, result.DrawID),
+		Text:  sb.String(),
+	}
+}
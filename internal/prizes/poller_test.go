@@ -0,0 +1,226 @@
+package prizes
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"lotto-notifications/internal/database"
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/notifier"
+	"lotto-notifications/internal/repository"
+	"lotto-notifications/internal/repository/repositorytest"
+	"lotto-notifications/internal/service"
+)
+
+// fakeService stores the prizes once published is set and fails while err is set
+type fakeService struct {
+	service.Service
+
+	repo      repository.Repository
+	published bool
+	err       error
+}
+
+func (s *fakeService) UpdatePrizes(ctx context.Context, results []models.Result) error {
+	if s.err != nil {
+		return s.err
+	}
+	if !s.published {
+		return nil
+	}
+	for _, result := range results {
+		err := s.repo.SavePrizes(ctx, []models.Prize{
+			{DrawID: result.DrawID, GameType: result.GameType, Tier: 1, Winners: 1, Amount: 2_000_000},
+			{DrawID: result.DrawID, GameType: result.GameType, Tier: 4, Winners: 5000, Amount: 24},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fakeMessenger records the messages it sends and fails while err is set
+type fakeMessenger struct {
+	err  error
+	sent []notifier.Message
+}
+
+func (m *fakeMessenger) ChannelType() models.ChannelType {
+	return models.ChannelTypeTelegram
+}
+
+func (m *fakeMessenger) Send(ctx context.Context, channel models.SubscriberChannel, msg notifier.Message) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// newWinner creates a subscriber with a telegram channel and a Lotto ticket matching 3 of 1..6
+func newWinner(t *testing.T, repo repository.Repository) {
+	t.Helper()
+	ctx := context.Background()
+	subscriber, err := repo.CreateSubscriber(ctx, models.Subscriber{Name: "Ala"})
+	if err != nil {
+		t.Fatalf("CreateSubscriber() error = %v", err)
+	}
+	channel := models.SubscriberChannel{SubscriberID: subscriber.ID, Type: models.ChannelTypeTelegram, Address: "42"}
+	if _, err := repo.AddSubscriberChannel(ctx, channel); err != nil {
+		t.Fatalf("AddSubscriberChannel() error = %v", err)
+	}
+	ticket := models.Ticket{SubscriberID: subscriber.ID, GameType: models.GameTypeLotto, Numbers: models.IntSlice{1, 2, 3, 40, 41, 42}}
+	if _, err := repo.CreateTicket(ctx, ticket); err != nil {
+		t.Fatalf("CreateTicket() error = %v", err)
+	}
+}
+
+// saveResult saves a Lotto result, scheduling its prize check
+func saveResult(t *testing.T, repo repository.Repository) {
+	t.Helper()
+	drawDate := time.Date(2026, time.October, 17, 20, 0, 0, 0, time.UTC)
+	draw := models.Draw{
+		DrawID:   7000,
+		GameType: models.GameTypeLotto,
+		DrawDate: drawDate,
+		Results: []models.Result{
+			{DrawID: 7000, GameType: models.GameTypeLotto, DrawDate: drawDate, Results: models.IntSlice{1, 2, 3, 4, 5, 6}},
+		},
+	}
+	if _, err := repo.SaveDraws(context.Background(), models.GameTypeLotto, []models.Draw{draw}, nil); err != nil {
+		t.Fatalf("SaveDraws() error = %v", err)
+	}
+}
+
+// makeDue moves the pending prize check back so it is due again
+func makeDue(t *testing.T, repo repository.Repository) {
+	t.Helper()
+	err := repo.MarkPrizeCheckFailed(context.Background(), models.GameTypeLotto, 7000, time.Now().Add(-time.Second), false)
+	if err != nil {
+		t.Fatalf("MarkPrizeCheckFailed() error = %v", err)
+	}
+}
+
+func dueChecks(t *testing.T, repo repository.Repository, now time.Time) []models.PrizeCheck {
+	t.Helper()
+	due, err := repo.GetDuePrizeChecks(context.Background(), now)
+	if err != nil {
+		t.Fatalf("GetDuePrizeChecks() error = %v", err)
+	}
+	return due
+}
+
+func TestCheckDueSendsPayoutOnce(t *testing.T) {
+	ctx := context.Background()
+	repo := repositorytest.New(t)
+	newWinner(t, repo)
+	saveResult(t, repo)
+	svc := &fakeService{repo: repo}
+	messenger := &fakeMessenger{}
+	p := NewPoller(svc, repo, messenger).(*poller)
+
+	if due := dueChecks(t, repo, time.Now()); len(due) != 1 {
+		t.Fatalf("GetDuePrizeChecks() = %v, want the saved result", due)
+	}
+
+	// not published yet, the check is retried after the backoff
+	if err := p.checkDue(ctx); err != nil {
+		t.Fatalf("checkDue() error = %v", err)
+	}
+	if len(messenger.sent) != 0 {
+		t.Fatalf("sent %d messages before the prizes were published", len(messenger.sent))
+	}
+	if due := dueChecks(t, repo, time.Now()); len(due) != 0 {
+		t.Fatalf("GetDuePrizeChecks() = %v, want the check backed off", due)
+	}
+	due := dueChecks(t, repo, time.Now().Add(retryBackoff))
+	if len(due) != 1 || due[0].Attempts != 1 {
+		t.Fatalf("GetDuePrizeChecks() after the backoff = %v, want one check with 1 attempt", due)
+	}
+
+	// published, but the channel fails, the payout is retried
+	makeDue(t, repo)
+	svc.published = true
+	messenger.err = errors.New("chat not found")
+	if err := p.checkDue(ctx); err != nil {
+		t.Fatalf("checkDue() error = %v", err)
+	}
+	if due := dueChecks(t, repo, time.Now().Add(maxRetryBackoff)); len(due) != 1 {
+		t.Fatalf("GetDuePrizeChecks() = %v, want the check kept pending", due)
+	}
+
+	makeDue(t, repo)
+	messenger.err = nil
+	if err := p.checkDue(ctx); err != nil {
+		t.Fatalf("checkDue() error = %v", err)
+	}
+	if len(messenger.sent) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(messenger.sent))
+	}
+	if want := "Lotto draw 7000 prizes"; messenger.sent[0].Title != want {
+		t.Errorf("title = %q, want %q", messenger.sent[0].Title, want)
+	}
+	if due := dueChecks(t, repo, time.Now().Add(maxRetryBackoff)); len(due) != 0 {
+		t.Fatalf("GetDuePrizeChecks() = %v, want the check done", due)
+	}
+
+	// a check repeated after a crash does not message the winner again
+	makeDue(t, repo)
+	if err := p.checkDue(ctx); err != nil {
+		t.Fatalf("checkDue() error = %v", err)
+	}
+	if len(messenger.sent) != 1 {
+		t.Errorf("sent %d messages, want the payout sent once", len(messenger.sent))
+	}
+}
+
+func TestCheckDueExpires(t *testing.T) {
+	ctx := context.Background()
+	repo := repositorytest.New(t)
+	saveResult(t, repo)
+	p := NewPoller(&fakeService{repo: repo, err: errors.New("breaker open")}, repo).(*poller)
+
+	if err := p.checkDue(ctx); err != nil {
+		t.Fatalf("checkDue() error = %v", err)
+	}
+	if due := dueChecks(t, repo, time.Now().Add(retryBackoff)); len(due) != 1 {
+		t.Fatalf("GetDuePrizeChecks() = %v, want the failed check retried", due)
+	}
+
+	// the draw was saved days ago and its prizes never showed up
+	db, err := database.GetDB()
+	if err != nil {
+		t.Fatalf("GetDB() error = %v", err)
+	}
+	if _, err := db.Exec(`UPDATE prize_checks SET created_at = ?`, time.Now().Add(-expireAfter)); err != nil {
+		t.Fatalf("failed to backdate the prize check: %v", err)
+	}
+	makeDue(t, repo)
+	if err := p.checkDue(ctx); err != nil {
+		t.Fatalf("checkDue() error = %v", err)
+	}
+	if due := dueChecks(t, repo, time.Now().Add(expireAfter)); len(due) != 0 {
+		t.Errorf("GetDuePrizeChecks() = %v, want the check expired", due)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: 15 * time.Minute},
+		{attempts: 2, want: 30 * time.Minute},
+		{attempts: 3, want: time.Hour},
+		{attempts: 4, want: maxRetryBackoff},
+		{attempts: 50, want: maxRetryBackoff},
+	}
+	for _, tt := range tests {
+		if got := backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/notifier"
+	"lotto-notifications/internal/repository"
+)
+
+const (
+	pollInterval = time.Minute
+	// minSleep keeps Run from spinning when a reminder is due again right away
+	minSleep = time.Second
+
+	retryBackoff    = 30 * time.Second
+	maxRetryBackoff = 10 * time.Minute
+)
+
+// Scheduler sends the subscribers' pre-draw reminders.
+// Reminders are persisted before they are due so they survive restarts.
+type Scheduler interface {
+	Run(ctx context.Context)
+}
+
+type Config struct {
+	// StaleAfter is how late a reminder may still be sent, later ones were missed during downtime and are skipped
+	StaleAfter time.Duration
+}
+
+type scheduler struct {
+	repo       repository.Repository
+	cfg        Config
+	messengers map[models.ChannelType]notifier.Messenger
+}
+
+func NewScheduler(repo repository.Repository, cfg Config, messengers ...notifier.Messenger) Scheduler {
+	byType := make(map[models.ChannelType]notifier.Messenger, len(messengers))
+	for _, messenger := range messengers {
+		byType[messenger.ChannelType()] = messenger
+	}
+	return &scheduler{
+		repo:       repo,
+		cfg:        cfg,
+		messengers: byType,
+	}
+}
+
+// Run schedules and sends reminders until the context is cancelled
+func (s *scheduler) Run(ctx context.Context) {
+	for {
+		if err := s.schedule(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("Failed to schedule reminders", "error", err)
+		}
+		if err := s.fireDue(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("Failed to send reminders", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.untilNext(ctx)):
+		}
+	}
+}
+
+// untilNext returns how long to sleep, waking up early for the next pending reminder
+func (s *scheduler) untilNext(ctx context.Context) time.Duration {
+	next, err := s.repo.GetNextPendingReminderTime(ctx)
+	if err != nil || next == nil {
+		return pollInterval
+	}
+	return max(min(time.Until(*next), pollInterval), minSleep)
+}
+
+// schedule persists a pending reminder for the next draw of every game with reminders.
+// Reminders whose time already passed are not scheduled.
+func (s *scheduler) schedule(ctx context.Context) error {
+	games, err := s.repo.GetGames(ctx, false)
+	if err != nil {
+		return fmt.Errorf("failed to get games: %w", err)
+	}
+
+	now := time.Now()
+	for _, game := range games {
+		drawDate := nextDrawDate(game, games)
+		if drawDate == nil || !drawDate.After(now) {
+			continue
+		}
+
+		reminders, err := s.repo.GetRemindersByGame(ctx, game.GameType)
+		if err != nil {
+			return fmt.Errorf("failed to get reminders: %w", err)
+		}
+		for _, reminder := range reminders {
+			fireAt := drawDate.Add(-reminder.Offset())
+			if fireAt.Before(now) {
+				continue
+			}
+			if err := s.repo.SchedulePendingReminder(ctx, reminder.ID, *drawDate, fireAt); err != nil {
+				return fmt.Errorf("failed to schedule reminder: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *scheduler) fireDue(ctx context.Context) error {
+	due, err := s.repo.GetDuePendingReminders(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to get due reminders: %w", err)
+	}
+
+	for _, pending := range due {
+		status, err := s.fire(ctx, pending)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			// left pending and retried with a backoff until it goes stale
+			nextAttemptAt := time.Now().Add(backoff(pending.Attempts + 1))
+			slog.Error("Failed to send reminder",
+				"reminderID", pending.ReminderID,
+				"game", pending.GameType,
+				"attempts", pending.Attempts+1,
+				"nextAttemptAt", nextAttemptAt,
+				"error", err,
+			)
+			if err := s.repo.MarkPendingReminderFailed(ctx, pending.ID, nextAttemptAt); err != nil {
+				slog.Error("Failed to mark reminder as failed", "id", pending.ID, "error", err)
+			}
+			continue
+		}
+		if err := s.repo.UpdatePendingReminderStatus(ctx, pending.ID, status); err != nil {
+			slog.Error("Failed to update reminder status", "id", pending.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// fire sends a due reminder and returns its new status, stale reminders are skipped
+func (s *scheduler) fire(ctx context.Context, pending models.PendingReminder) (models.PendingReminderStatus, error) {
+	if late := time.Since(pending.FireAt); late > s.cfg.StaleAfter {
+		slog.Warn("Skipping stale reminder",
+			"reminderID", pending.ReminderID,
+			"game", pending.GameType,
+			"drawDate", pending.DrawDate,
+			"late", late,
+		)
+		return models.PendingReminderStatusSkipped, nil
+	}
+
+	game, err := s.repo.GetGame(ctx, string(pending.GameType))
+	if err != nil {
+		return "", fmt.Errorf("failed to get game: %w", err)
+	}
+	games := []models.Game{game}
+	if game.TiedTo != nil {
+		parent, err := s.repo.GetGame(ctx, *game.TiedTo)
+		if err != nil {
+			return "", fmt.Errorf("failed to get game: %w", err)
+		}
+		games = append(games, parent)
+	}
+	// the draw was moved since the reminder was scheduled, the new date gets its own reminder
+	drawDate := nextDrawDate(game, games)
+	if drawDate == nil || !drawDate.Equal(pending.DrawDate) {
+		slog.Info("Draw date changed, skipping reminder", "reminderID", pending.ReminderID, "game", pending.GameType)
+		return models.PendingReminderStatusSkipped, nil
+	}
+
+	channels, err := s.repo.GetSubscriberChannels(ctx, pending.SubscriberID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get subscriber channels: %w", err)
+	}
+
+	msg := reminderMessage(game, pending)
+	var errs []error
+	sent := 0
+	for _, channel := range channels {
+		messenger, ok := s.messengers[channel.Type]
+		if !ok {
+			continue
+		}
+		if err := messenger.Send(ctx, channel, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s channel %d: %w", channel.Type, channel.ID, err))
+			continue
+		}
+		sent++
+	}
+	if sent == 0 && len(errs) > 0 {
+		return "", errors.Join(errs...)
+	}
+
+	slog.Info("Sent reminder", "game", pending.GameType, "subscriberID", pending.SubscriberID, "channels", sent)
+	return models.PendingReminderStatusSent, nil
+}
+
+// backoff returns the delay before retrying a reminder, doubling with every failed attempt
+func backoff(attempts int) time.Duration {
+	delay := retryBackoff
+	for i := 1; i < attempts && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryBackoff)
+}
+
+// nextDrawDate returns the game's next draw, tied games are drawn together with their main game
+func nextDrawDate(game models.Game, games []models.Game) *time.Time {
+	if game.TiedTo == nil {
+		return game.NextDrawDate
+	}
+	for _, main := range games {
+		if string(main.GameType) == *game.TiedTo {
+			return main.NextDrawDate
+		}
+	}
+	return nil
+}
+
+func reminderMessage(game models.Game, pending models.PendingReminder) notifier.Message {
+	text := fmt.Sprintf("The %s draw is at %s, in %s. Don't forget your coupon!",
+		game.GameType, notifier.FormatDate(pending.DrawDate), notifier.FormatOffset(time.Until(pending.DrawDate).Round(time.Minute)))
+	if game.ClosestPrizeValue != nil {
+		text += "\nJackpot: " + notifier.FormatPLN(*game.ClosestPrizeValue)
+	}
+	return notifier.Message{
+		Title: fmt.Sprintf("%s draw reminder", game.GameType),
+		Text:  text,
+	}
+}
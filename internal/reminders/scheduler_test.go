@@ -0,0 +1,128 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/notifier"
+	"lotto-notifications/internal/repository"
+	"lotto-notifications/internal/repository/repositorytest"
+)
+
+// fakeMessenger counts the messages it is asked to send and fails while err is set
+type fakeMessenger struct {
+	err   error
+	calls int
+}
+
+func (m *fakeMessenger) ChannelType() models.ChannelType {
+	return models.ChannelTypeTelegram
+}
+
+func (m *fakeMessenger) Send(ctx context.Context, channel models.SubscriberChannel, msg notifier.Message) error {
+	m.calls++
+	return m.err
+}
+
+// newDueReminder schedules a Lotto reminder that was due a second ago and returns its pending reminder
+func newDueReminder(t *testing.T, repo repository.Repository) models.PendingReminder {
+	t.Helper()
+	ctx := context.Background()
+
+	drawDate := time.Now().Add(30 * time.Minute).Truncate(time.Second)
+	if err := repo.UpdateGames(ctx, []models.Game{{GameType: models.GameTypeLotto, NextDrawDate: &drawDate}}); err != nil {
+		t.Fatalf("UpdateGames() error = %v", err)
+	}
+	subscriber, err := repo.CreateSubscriber(ctx, models.Subscriber{Name: "Ala"})
+	if err != nil {
+		t.Fatalf("CreateSubscriber() error = %v", err)
+	}
+	channel := models.SubscriberChannel{SubscriberID: subscriber.ID, Type: models.ChannelTypeTelegram, Address: "42"}
+	if _, err := repo.AddSubscriberChannel(ctx, channel); err != nil {
+		t.Fatalf("AddSubscriberChannel() error = %v", err)
+	}
+	if err := repo.SetReminders(ctx, subscriber.ID, models.GameTypeLotto, []time.Duration{time.Hour}); err != nil {
+		t.Fatalf("SetReminders() error = %v", err)
+	}
+	reminders, err := repo.GetReminders(ctx, subscriber.ID)
+	if err != nil || len(reminders) != 1 {
+		t.Fatalf("GetReminders() = %v, %v, want one reminder", reminders, err)
+	}
+	if err := repo.SchedulePendingReminder(ctx, reminders[0].ID, drawDate, time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("SchedulePendingReminder() error = %v", err)
+	}
+
+	due, err := repo.GetDuePendingReminders(ctx, time.Now())
+	if err != nil || len(due) != 1 {
+		t.Fatalf("GetDuePendingReminders() = %v, %v, want one reminder", due, err)
+	}
+	return due[0]
+}
+
+func TestFireDueBacksOffFailedReminder(t *testing.T) {
+	ctx := context.Background()
+	repo := repositorytest.New(t)
+	pending := newDueReminder(t, repo)
+	messenger := &fakeMessenger{err: errors.New("chat not found")}
+	s := NewScheduler(repo, Config{StaleAfter: time.Hour}, messenger).(*scheduler)
+
+	for range 3 {
+		if err := s.fireDue(ctx); err != nil {
+			t.Fatalf("fireDue() error = %v", err)
+		}
+	}
+	if messenger.calls != 1 {
+		t.Fatalf("sent %d times, want a single attempt before the backoff passed", messenger.calls)
+	}
+
+	next, err := repo.GetNextPendingReminderTime(ctx)
+	if err != nil || next == nil {
+		t.Fatalf("GetNextPendingReminderTime() = %v, %v, want the retry", next, err)
+	}
+	if wait := time.Until(*next); wait < retryBackoff-time.Second || wait > retryBackoff {
+		t.Errorf("next attempt in %v, want %v", wait, retryBackoff)
+	}
+	if wait := s.untilNext(ctx); wait < retryBackoff-time.Second || wait > retryBackoff {
+		t.Errorf("untilNext() = %v, want %v", wait, retryBackoff)
+	}
+
+	// the backoff passed and the channel works again
+	if err := repo.MarkPendingReminderFailed(ctx, pending.ID, time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("MarkPendingReminderFailed() error = %v", err)
+	}
+	if wait := s.untilNext(ctx); wait != minSleep {
+		t.Errorf("untilNext() for an overdue reminder = %v, want %v", wait, minSleep)
+	}
+	messenger.err = nil
+	if err := s.fireDue(ctx); err != nil {
+		t.Fatalf("fireDue() error = %v", err)
+	}
+	if messenger.calls != 2 {
+		t.Errorf("sent %d times, want 2", messenger.calls)
+	}
+	if next, err := repo.GetNextPendingReminderTime(ctx); err != nil || next != nil {
+		t.Errorf("GetNextPendingReminderTime() = %v, %v, want nothing pending", next, err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: 30 * time.Second},
+		{attempts: 2, want: time.Minute},
+		{attempts: 3, want: 2 * time.Minute},
+		{attempts: 5, want: 8 * time.Minute},
+		{attempts: 6, want: maxRetryBackoff},
+		{attempts: 50, want: maxRetryBackoff},
+	}
+	for _, tt := range tests {
+		if got := backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
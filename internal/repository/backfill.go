@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"lotto-notifications/internal/models"
+)
+
+func (r *repository) GetBackfillCheckpoint(ctx context.Context, gameType models.GameType) (models.BackfillCheckpoint, error) {
+	stmt := `SELECT * FROM backfill_checkpoints WHERE game_type = ?`
+	checkpoint := models.BackfillCheckpoint{}
+	err := r.db.GetContext(ctx, &checkpoint, stmt, gameType)
+	if err != nil {
+		return models.BackfillCheckpoint{}, err
+	}
+	return checkpoint, nil
+}
+
+// SaveBackfillCheckpoint stores the progress of the game's backfill, replacing the previous one
+func (r *repository) SaveBackfillCheckpoint(ctx context.Context, checkpoint models.BackfillCheckpoint) error {
+	stmt := `INSERT INTO backfill_checkpoints (game_type, from_date, to_date, next_page, inserted, completed_at, updated_at)
+		VALUES (:game_type, :from_date, :to_date, :next_page, :inserted, :completed_at, :updated_at)
+		ON CONFLICT (game_type) DO UPDATE SET
+			from_date = excluded.from_date,
+			to_date = excluded.to_date,
+			next_page = excluded.next_page,
+			inserted = excluded.inserted,
+			completed_at = excluded.completed_at,
+			updated_at = excluded.updated_at`
+	checkpoint.UpdatedAt = time.Now()
+	_, err := r.db.NamedExecContext(ctx, stmt, checkpoint)
+	return err
+}
@@ -0,0 +1,145 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/repository"
+	"lotto-notifications/internal/repository/repositorytest"
+)
+
+var drawDate = time.Date(2026, time.October, 17, 20, 0, 0, 0, time.UTC)
+
+// lottoDraw returns a Lotto draw with a result set for every game type given
+func lottoDraw(drawID uint, gameTypes ...models.GameType) models.Draw {
+	parentGameType := models.GameTypeLotto
+	draw := models.Draw{DrawID: drawID, GameType: parentGameType, DrawDate: drawDate}
+	for idx, gameType := range gameTypes {
+		draw.Results = append(draw.Results, models.Result{
+			DrawID:         drawID,
+			GameType:       gameType,
+			DrawDate:       drawDate,
+			Results:        models.IntSlice{1, 2, 3, 4, 5, 6 + idx},
+			ParentDrawID:   &drawID,
+			ParentGameType: &parentGameType,
+		})
+	}
+	return draw
+}
+
+func outboxMessages(t *testing.T, repo repository.Repository) []models.OutboxMessage {
+	t.Helper()
+	messages, err := repo.GetOutboxMessages(context.Background(), models.OutboxStatusPending)
+	if err != nil {
+		t.Fatalf("GetOutboxMessages() error = %v", err)
+	}
+	return messages
+}
+
+func TestSaveDrawsStoresEveryResultSet(t *testing.T) {
+	ctx := context.Background()
+	repo := repositorytest.New(t)
+
+	draws := []models.Draw{
+		lottoDraw(7000, models.GameTypeLotto, models.GameTypeLottoPlus),
+		lottoDraw(7001, models.GameTypeLotto, models.GameTypeLottoPlus),
+	}
+	inserted, err := repo.SaveDraws(ctx, models.GameTypeLotto, draws, []string{"email", "telegram"})
+	if err != nil {
+		t.Fatalf("SaveDraws() error = %v", err)
+	}
+	if len(inserted) != 4 {
+		t.Errorf("SaveDraws() returned %d results, want 4", len(inserted))
+	}
+
+	for _, drawID := range []uint{7000, 7001} {
+		draw, err := repo.GetDraw(ctx, models.GameTypeLotto, drawID)
+		if err != nil {
+			t.Fatalf("GetDraw(%d) error = %v", drawID, err)
+		}
+		var got []models.GameType
+		for _, result := range draw.Results {
+			got = append(got, result.GameType)
+		}
+		want := []models.GameType{models.GameTypeLotto, models.GameTypeLottoPlus}
+		if !slices.Equal(got, want) {
+			t.Errorf("draw %d has results %v, want %v", drawID, got, want)
+		}
+	}
+	if messages := outboxMessages(t, repo); len(messages) != 2 {
+		t.Errorf("queued %d outbox messages, want one per channel", len(messages))
+	}
+}
+
+func TestSaveDrawsIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	repo := repositorytest.New(t)
+	draws := []models.Draw{lottoDraw(7000, models.GameTypeLotto, models.GameTypeLottoPlus)}
+
+	if _, err := repo.SaveDraws(ctx, models.GameTypeLotto, draws, []string{"email"}); err != nil {
+		t.Fatalf("SaveDraws() error = %v", err)
+	}
+	inserted, err := repo.SaveDraws(ctx, models.GameTypeLotto, draws, []string{"email"})
+	if err != nil {
+		t.Fatalf("SaveDraws() again error = %v", err)
+	}
+	if len(inserted) != 0 {
+		t.Errorf("SaveDraws() again returned %d results, want none", len(inserted))
+	}
+	if messages := outboxMessages(t, repo); len(messages) != 1 {
+		t.Errorf("queued %d outbox messages, want the first save's only", len(messages))
+	}
+
+	// a result set missing the first time is added to the stored draw
+	if _, err := repo.SaveDraws(ctx, models.GameTypeLotto, []models.Draw{lottoDraw(7001, models.GameTypeLotto)}, nil); err != nil {
+		t.Fatalf("SaveDraws() error = %v", err)
+	}
+	draws = []models.Draw{lottoDraw(7001, models.GameTypeLotto, models.GameTypeLottoPlus)}
+	inserted, err = repo.SaveDraws(ctx, models.GameTypeLotto, draws, nil)
+	if err != nil {
+		t.Fatalf("SaveDraws() error = %v", err)
+	}
+	if len(inserted) != 1 || inserted[0].GameType != models.GameTypeLottoPlus {
+		t.Errorf("SaveDraws() returned %v, want the new result set only", inserted)
+	}
+	draw, err := repo.GetDraw(ctx, models.GameTypeLotto, 7001)
+	if err != nil {
+		t.Fatalf("GetDraw() error = %v", err)
+	}
+	if len(draw.Results) != 2 {
+		t.Errorf("draw has %d results, want 2", len(draw.Results))
+	}
+}
+
+func TestSaveDrawsSkipsDrawWithoutResults(t *testing.T) {
+	ctx := context.Background()
+	repo := repositorytest.New(t)
+
+	// every result set of the draw was quarantined
+	inserted, err := repo.SaveDraws(ctx, models.GameTypeLotto, []models.Draw{lottoDraw(7000)}, []string{"email"})
+	if err != nil {
+		t.Fatalf("SaveDraws() error = %v", err)
+	}
+	if len(inserted) != 0 {
+		t.Errorf("SaveDraws() returned %d results, want none", len(inserted))
+	}
+	if _, err := repo.GetDraw(ctx, models.GameTypeLotto, 7000); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetDraw() error = %v, want %v", err, sql.ErrNoRows)
+	}
+	if messages := outboxMessages(t, repo); len(messages) != 0 {
+		t.Errorf("queued %d outbox messages, want none", len(messages))
+	}
+
+	// the draw is stored once a valid result comes in
+	if _, err := repo.InsertDraws(ctx, []models.Draw{lottoDraw(7000, models.GameTypeLotto)}); err != nil {
+		t.Fatalf("InsertDraws() error = %v", err)
+	}
+	if _, err := repo.GetDraw(ctx, models.GameTypeLotto, 7000); err != nil {
+		t.Errorf("GetDraw() error = %v", err)
+	}
+}
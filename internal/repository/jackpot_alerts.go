@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lotto-notifications/internal/models"
+)
+
+// SetJackpotAlert creates the subscriber's alert for the game or replaces its threshold,
+// a changed threshold starts over as if the alert never fired
+func (r *repository) SetJackpotAlert(
+	ctx context.Context, subscriberID int64, gameType models.GameType, threshold float64,
+) (models.JackpotAlert, error) {
+	stmt := `INSERT INTO jackpot_alerts (subscriber_id, game_type, threshold, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (subscriber_id, game_type) DO UPDATE SET
+			threshold = excluded.threshold,
+			notified_at = NULL,
+			notified_value = NULL`
+	_, err := r.db.ExecContext(ctx, stmt, subscriberID, gameType, threshold, time.Now())
+	if err != nil {
+		return models.JackpotAlert{}, err
+	}
+
+	alert := models.JackpotAlert{}
+	err = r.db.GetContext(ctx, &alert,
+		`SELECT * FROM jackpot_alerts WHERE subscriber_id = ? AND game_type = ?`, subscriberID, gameType)
+	if err != nil {
+		return models.JackpotAlert{}, fmt.Errorf("failed to get jackpot alert: %w", err)
+	}
+	return alert, nil
+}
+
+func (r *repository) GetJackpotAlerts(ctx context.Context, subscriberID int64) ([]models.JackpotAlert, error) {
+	stmt := `SELECT * FROM jackpot_alerts WHERE subscriber_id = ? ORDER BY game_type`
+	alerts := []models.JackpotAlert{}
+	err := r.db.SelectContext(ctx, &alerts, stmt, subscriberID)
+	if err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+func (r *repository) GetJackpotAlertsByGame(ctx context.Context, gameType models.GameType) ([]models.JackpotAlert, error) {
+	stmt := `SELECT * FROM jackpot_alerts WHERE game_type = ? ORDER BY id`
+	alerts := []models.JackpotAlert{}
+	err := r.db.SelectContext(ctx, &alerts, stmt, gameType)
+	if err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// UpdateJackpotAlertState records that the alert fired at the given jackpot value, nil values reset it
+func (r *repository) UpdateJackpotAlertState(
+	ctx context.Context, id int64, notifiedAt *time.Time, notifiedValue *float64,
+) error {
+	stmt := `UPDATE jackpot_alerts SET notified_at = ?, notified_value = ? WHERE id = ?`
+	res, err := r.db.ExecContext(ctx, stmt, notifiedAt, notifiedValue, id)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res)
+}
+
+func (r *repository) DeleteJackpotAlert(ctx context.Context, subscriberID int64, gameType models.GameType) error {
+	stmt := `DELETE FROM jackpot_alerts WHERE subscriber_id = ? AND game_type = ?`
+	res, err := r.db.ExecContext(ctx, stmt, subscriberID, gameType)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res)
+}
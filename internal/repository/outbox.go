@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"lotto-notifications/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func enqueueOutbox(
+	ctx context.Context, trx *sqlx.Tx, gameType models.GameType, results []models.Result, channels []string,
+) error {
+	stmt := `INSERT INTO outbox (channel, game_type, payload, status, attempts, next_attempt_at, created_at, updated_at)
+		VALUES (:channel, :game_type, :payload, :status, :attempts, :next_attempt_at, :created_at, :updated_at)`
+
+	payload, err := json.Marshal(results)
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox payload: %w", err)
+	}
+
+	now := time.Now().UTC()
+	for _, channel := range channels {
+		msg := models.OutboxMessage{
+			Channel:       channel,
+			GameType:      gameType,
+			Payload:       string(payload),
+			Status:        models.OutboxStatusPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		if _, err := trx.NamedExecContext(ctx, stmt, msg); err != nil {
+			return fmt.Errorf("failed to enqueue outbox message: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetDueOutboxMessages returns the pending messages whose next attempt is due, oldest first.
+// The outbox times are stored in UTC so they compare as text.
+func (r *repository) GetDueOutboxMessages(ctx context.Context, now time.Time, limit int) ([]models.OutboxMessage, error) {
+	stmt := `SELECT * FROM outbox WHERE status = ? AND next_attempt_at <= ? ORDER BY id LIMIT ?`
+	messages := []models.OutboxMessage{}
+	err := r.db.SelectContext(ctx, &messages, stmt, models.OutboxStatusPending, now.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (r *repository) GetOutboxMessages(ctx context.Context, status models.OutboxStatus) ([]models.OutboxMessage, error) {
+	stmt := `SELECT * FROM outbox WHERE status = ? ORDER BY id`
+	messages := []models.OutboxMessage{}
+	err := r.db.SelectContext(ctx, &messages, stmt, status)
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (r *repository) MarkOutboxSent(ctx context.Context, id int64) error {
+	stmt := `UPDATE outbox SET status = ?, attempts = attempts + 1, last_error = NULL, updated_at = ? WHERE id = ?`
+	res, err := r.db.ExecContext(ctx, stmt, models.OutboxStatusSent, time.Now().UTC(), id)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res)
+}
+
+// MarkOutboxFailed records a failed attempt and schedules the next one,
+// or moves the message to the dead-letter state
+func (r *repository) MarkOutboxFailed(
+	ctx context.Context, id int64, lastError string, nextAttemptAt time.Time, dead bool,
+) error {
+	status := models.OutboxStatusPending
+	if dead {
+		status = models.OutboxStatusDead
+	}
+	stmt := `UPDATE outbox SET
+		status = ?,
+		attempts = attempts + 1,
+		last_error = ?,
+		next_attempt_at = ?,
+		updated_at = ?
+	WHERE id = ?`
+	res, err := r.db.ExecContext(ctx, stmt, status, lastError, nextAttemptAt.UTC(), time.Now().UTC(), id)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res)
+}
+
+// RequeueOutboxMessage gives a dead message a fresh set of delivery attempts
+func (r *repository) RequeueOutboxMessage(ctx context.Context, id int64) error {
+	stmt := `UPDATE outbox SET status = ?, attempts = 0, next_attempt_at = ?, updated_at = ? WHERE id = ? AND status = ?`
+	now := time.Now().UTC()
+	res, err := r.db.ExecContext(ctx, stmt, models.OutboxStatusPending, now, now, id, models.OutboxStatusDead)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res)
+}
+
+// ExpandOutboxMessage replaces a pending message with one pending message per target,
+// the children share its payload and start with a fresh set of delivery attempts
+func (r *repository) ExpandOutboxMessage(
+	ctx context.Context, id int64, targets []string,
+) ([]models.OutboxMessage, error) {
+	insert := `INSERT INTO outbox (
+		channel, parent_id, target, game_type, payload, status, attempts, next_attempt_at, created_at, updated_at
+	)
+	SELECT channel, id, ?, game_type, payload, ?, 0, ?, ?, ? FROM outbox WHERE id = ?
+	ON CONFLICT (parent_id, target) DO NOTHING`
+
+	trx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer trx.Rollback()
+
+	now := time.Now().UTC()
+	res, err := trx.ExecContext(ctx, `UPDATE outbox SET status = ?, updated_at = ? WHERE id = ? AND status = ?`,
+		models.OutboxStatusExpanded, now, id, models.OutboxStatusPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark outbox message as expanded: %w", err)
+	}
+	if err := expectAffected(res); err != nil {
+		return nil, err
+	}
+	for _, target := range targets {
+		if _, err := trx.ExecContext(ctx, insert, target, models.OutboxStatusPending, now, now, now, id); err != nil {
+			return nil, fmt.Errorf("failed to enqueue outbox message for %s: %w", target, err)
+		}
+	}
+
+	messages := []models.OutboxMessage{}
+	err = trx.SelectContext(ctx, &messages, `SELECT * FROM outbox WHERE parent_id = ? ORDER BY id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expanded outbox messages: %w", err)
+	}
+
+	err = trx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return messages, nil
+}
@@ -0,0 +1,41 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/repository/repositorytest"
+)
+
+func TestGetDueOutboxMessagesIgnoresTimeZones(t *testing.T) {
+	ctx := context.Background()
+	repo := repositorytest.New(t)
+	draws := []models.Draw{lottoDraw(7000, models.GameTypeLotto)}
+	if _, err := repo.SaveDraws(ctx, models.GameTypeLotto, draws, []string{"email"}); err != nil {
+		t.Fatalf("SaveDraws() error = %v", err)
+	}
+
+	now := time.Now()
+	tests := []struct {
+		name string
+		now  time.Time
+		want int
+	}{
+		{name: "east of UTC", now: now.In(time.FixedZone("UTC+14", 14*60*60)), want: 1},
+		{name: "west of UTC", now: now.In(time.FixedZone("UTC-10", -10*60*60)), want: 1},
+		{name: "before the message", now: now.Add(-time.Hour).In(time.FixedZone("UTC+14", 14*60*60)), want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages, err := repo.GetDueOutboxMessages(ctx, tt.now, 10)
+			if err != nil {
+				t.Fatalf("GetDueOutboxMessages() error = %v", err)
+			}
+			if len(messages) != tt.want {
+				t.Errorf("GetDueOutboxMessages() returned %d messages, want %d", len(messages), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lotto-notifications/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// SavePrizes stores the prizes, replacing the ones already stored for the same tiers
+// since the numbers of winners are corrected after the draw
+func (r *repository) SavePrizes(ctx context.Context, prizes []models.Prize) error {
+	stmt := `INSERT INTO draw_prizes (draw_id, game_type, tier, winners, amount, updated_at)
+		VALUES (:draw_id, :game_type, :tier, :winners, :amount, :updated_at)
+		ON CONFLICT (draw_id, game_type, tier) DO UPDATE SET
+			winners = excluded.winners,
+			amount = excluded.amount,
+			updated_at = excluded.updated_at`
+
+	trx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer trx.Rollback()
+
+	now := time.Now()
+	for _, prize := range prizes {
+		prize.UpdatedAt = now
+		if _, err := trx.NamedExecContext(ctx, stmt, prize); err != nil {
+			return fmt.Errorf("failed to execute statement: %w", err)
+		}
+	}
+
+	err = trx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetPrizes returns the prizes of a draw ordered by tier, empty when they were not published yet
+func (r *repository) GetPrizes(ctx context.Context, gameType models.GameType, drawID uint) ([]models.Prize, error) {
+	stmt := `SELECT * FROM draw_prizes WHERE game_type = ? AND draw_id = ? ORDER BY tier`
+	prizes := []models.Prize{}
+	err := r.db.SelectContext(ctx, &prizes, stmt, gameType, drawID)
+	if err != nil {
+		return nil, err
+	}
+	return prizes, nil
+}
+
+// schedulePrizeChecks queues the results for the prize poller, the first check is due right away
+func schedulePrizeChecks(ctx context.Context, trx *sqlx.Tx, results []models.Result) error {
+	stmt := `INSERT INTO prize_checks (draw_id, game_type, status, attempts, next_check_at, created_at, updated_at)
+		VALUES (?, ?, ?, 0, ?, ?, ?)
+		ON CONFLICT (draw_id, game_type) DO NOTHING`
+	now := time.Now().UTC()
+	for _, result := range results {
+		_, err := trx.ExecContext(ctx, stmt,
+			result.DrawID, result.GameType, models.PrizeCheckStatusPending, now, now, now)
+		if err != nil {
+			return fmt.Errorf("failed to schedule prize check: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetDuePrizeChecks returns the pending prize checks that are due, oldest first
+func (r *repository) GetDuePrizeChecks(ctx context.Context, now time.Time) ([]models.PrizeCheck, error) {
+	stmt := `SELECT * FROM prize_checks WHERE status = ? AND next_check_at <= ? ORDER BY next_check_at, draw_id`
+	checks := []models.PrizeCheck{}
+	err := r.db.SelectContext(ctx, &checks, stmt, models.PrizeCheckStatusPending, now.UTC())
+	if err != nil {
+		return nil, err
+	}
+	return checks, nil
+}
+
+func (r *repository) MarkPrizeCheckDone(ctx context.Context, gameType models.GameType, drawID uint) error {
+	stmt := `UPDATE prize_checks SET status = ?, attempts = attempts + 1, updated_at = ?
+		WHERE game_type = ? AND draw_id = ?`
+	res, err := r.db.ExecContext(ctx, stmt, models.PrizeCheckStatusDone, time.Now().UTC(), gameType, drawID)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res)
+}
+
+// MarkPrizeCheckFailed records a check that found no prizes and schedules the next one,
+// or gives up on the draw when expired is set
+func (r *repository) MarkPrizeCheckFailed(
+	ctx context.Context, gameType models.GameType, drawID uint, nextCheckAt time.Time, expired bool,
+) error {
+	status := models.PrizeCheckStatusPending
+	if expired {
+		status = models.PrizeCheckStatusExpired
+	}
+	stmt := `UPDATE prize_checks SET status = ?, attempts = attempts + 1, next_check_at = ?, updated_at = ?
+		WHERE game_type = ? AND draw_id = ?`
+	res, err := r.db.ExecContext(ctx, stmt, status, nextCheckAt.UTC(), time.Now().UTC(), gameType, drawID)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res)
+}
+
+// GetPayoutSubscribers returns the subscribers who were already told what the draw paid them
+func (r *repository) GetPayoutSubscribers(ctx context.Context, gameType models.GameType, drawID uint) ([]int64, error) {
+	stmt := `SELECT subscriber_id FROM payout_notifications WHERE game_type = ? AND draw_id = ? ORDER BY subscriber_id`
+	ids := []int64{}
+	err := r.db.SelectContext(ctx, &ids, stmt, gameType, drawID)
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *repository) MarkPayoutNotified(ctx context.Context, gameType models.GameType, drawID uint, subscriberID int64) error {
+	stmt := `INSERT INTO payout_notifications (draw_id, game_type, subscriber_id, sent_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (draw_id, game_type, subscriber_id) DO NOTHING`
+	_, err := r.db.ExecContext(ctx, stmt, drawID, gameType, subscriberID, time.Now().UTC())
+	return err
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"lotto-notifications/internal/models"
+)
+
+// SetReminders replaces the subscriber's reminders for the game with the given offsets,
+// reminders that are already scheduled for a removed offset are dropped
+func (r *repository) SetReminders(
+	ctx context.Context, subscriberID int64, gameType models.GameType, offsets []time.Duration,
+) error {
+	trx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer trx.Rollback()
+
+	for _, stmt := range []string{
+		`DELETE FROM pending_reminders WHERE reminder_id IN
+			(SELECT id FROM reminders WHERE subscriber_id = ? AND game_type = ?)`,
+		`DELETE FROM reminders WHERE subscriber_id = ? AND game_type = ?`,
+	} {
+		if _, err := trx.ExecContext(ctx, stmt, subscriberID, gameType); err != nil {
+			return fmt.Errorf("failed to execute statement: %w", err)
+		}
+	}
+
+	stmt := `INSERT INTO reminders (subscriber_id, game_type, offset_minutes, created_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (subscriber_id, game_type, offset_minutes) DO NOTHING`
+	now := time.Now()
+	for _, offset := range offsets {
+		_, err := trx.ExecContext(ctx, stmt, subscriberID, gameType, int(offset/time.Minute), now)
+		if err != nil {
+			return fmt.Errorf("failed to execute statement: %w", err)
+		}
+	}
+
+	err = trx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *repository) GetReminders(ctx context.Context, subscriberID int64) ([]models.Reminder, error) {
+	stmt := `SELECT * FROM reminders WHERE subscriber_id = ? ORDER BY game_type, offset_minutes DESC`
+	reminders := []models.Reminder{}
+	err := r.db.SelectContext(ctx, &reminders, stmt, subscriberID)
+	if err != nil {
+		return nil, err
+	}
+	return reminders, nil
+}
+
+func (r *repository) GetRemindersByGame(ctx context.Context, gameType models.GameType) ([]models.Reminder, error) {
+	stmt := `SELECT * FROM reminders WHERE game_type = ? ORDER BY id`
+	reminders := []models.Reminder{}
+	err := r.db.SelectContext(ctx, &reminders, stmt, gameType)
+	if err != nil {
+		return nil, err
+	}
+	return reminders, nil
+}
+
+// SchedulePendingReminder schedules the reminder for a draw, scheduling the same draw twice does nothing
+func (r *repository) SchedulePendingReminder(
+	ctx context.Context, reminderID int64, drawDate, fireAt time.Time,
+) error {
+	stmt := `INSERT INTO pending_reminders (reminder_id, draw_date, fire_at, status, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (reminder_id, draw_date) DO NOTHING`
+	_, err := r.db.ExecContext(ctx, stmt, reminderID, drawDate, fireAt, models.PendingReminderStatusPending, time.Now())
+	return err
+}
+
+// GetDuePendingReminders returns the pending reminders that should have fired by now, oldest first.
+// Failed reminders are due again once their next attempt is.
+func (r *repository) GetDuePendingReminders(ctx context.Context, now time.Time) ([]models.PendingReminder, error) {
+	stmt := `SELECT p.*, r.subscriber_id, r.game_type
+	FROM pending_reminders p
+	JOIN reminders r ON r.id = p.reminder_id
+	WHERE p.status = ? AND p.fire_at <= ? AND (p.next_attempt_at IS NULL OR p.next_attempt_at <= ?)
+	ORDER BY p.fire_at, p.id`
+	reminders := []models.PendingReminder{}
+	err := r.db.SelectContext(ctx, &reminders, stmt, models.PendingReminderStatusPending, now, now.UTC())
+	if err != nil {
+		return nil, err
+	}
+	return reminders, nil
+}
+
+// GetNextPendingReminderTime returns when the next pending reminder is due, nil when none is scheduled
+func (r *repository) GetNextPendingReminderTime(ctx context.Context) (*time.Time, error) {
+	// the columns are ordered separately, fire_at and next_attempt_at are not written in the same zone
+	var next *time.Time
+	for _, stmt := range []string{
+		`SELECT fire_at FROM pending_reminders WHERE status = ? AND next_attempt_at IS NULL
+			ORDER BY fire_at LIMIT 1`,
+		`SELECT next_attempt_at FROM pending_reminders WHERE status = ? AND next_attempt_at IS NOT NULL
+			ORDER BY next_attempt_at LIMIT 1`,
+	} {
+		var due time.Time
+		err := r.db.GetContext(ctx, &due, stmt, models.PendingReminderStatusPending)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if next == nil || due.Before(*next) {
+			next = &due
+		}
+	}
+	return next, nil
+}
+
+func (r *repository) UpdatePendingReminderStatus(
+	ctx context.Context, id int64, status models.PendingReminderStatus,
+) error {
+	stmt := `UPDATE pending_reminders SET status = ?, updated_at = ? WHERE id = ?`
+	res, err := r.db.ExecContext(ctx, stmt, status, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res)
+}
+
+// MarkPendingReminderFailed records a failed send, the reminder stays pending until nextAttemptAt.
+// next_attempt_at is stored in UTC, GetDuePendingReminders compares it as such.
+func (r *repository) MarkPendingReminderFailed(ctx context.Context, id int64, nextAttemptAt time.Time) error {
+	stmt := `UPDATE pending_reminders SET attempts = attempts + 1, next_attempt_at = ?, updated_at = ? WHERE id = ?`
+	res, err := r.db.ExecContext(ctx, stmt, nextAttemptAt.UTC(), time.Now(), id)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"time"
+
 	"lotto-notifications/internal/models"
 
 	"github.com/jmoiron/sqlx"
@@ -13,9 +15,82 @@ type Repository interface {
 	GetGames(ctx context.Context, independentOnly bool) ([]models.Game, error)
 	GetGame(ctx context.Context, gameType string) (models.Game, error)
 	GetResults(ctx context.Context, gameType string) ([]models.Result, error)
+	GetResultsPage(ctx context.Context, gameType string, query ResultsQuery) ([]models.Result, error)
 	GetNewestResult(ctx context.Context, gameType string) (models.Result, error)
+	GetResult(ctx context.Context, gameType models.GameType, drawID uint) (models.Result, error)
 	UpdateGames(ctx context.Context, games []models.Game) error
-	InsertResults(ctx context.Context, results []models.Result) error
+	InsertDraws(ctx context.Context, draws []models.Draw) ([]models.Result, error)
+	SaveDraws(ctx context.Context, gameType models.GameType, draws []models.Draw, channels []string) ([]models.Result, error)
+	GetDraw(ctx context.Context, gameType models.GameType, drawID uint) (models.Draw, error)
+	QuarantineResult(ctx context.Context, result models.QuarantinedResult) error
+	GetQuarantinedResults(ctx context.Context, gameType string) ([]models.QuarantinedResult, error)
+
+	CreateSubscriber(ctx context.Context, subscriber models.Subscriber) (models.Subscriber, error)
+	GetSubscribers(ctx context.Context) ([]models.Subscriber, error)
+	GetSubscriber(ctx context.Context, id int64) (models.Subscriber, error)
+	UpdateSubscriber(ctx context.Context, subscriber models.Subscriber) error
+	DeleteSubscriber(ctx context.Context, id int64) error
+
+	AddSubscriberChannel(ctx context.Context, channel models.SubscriberChannel) (models.SubscriberChannel, error)
+	GetSubscriberChannels(ctx context.Context, subscriberID int64) ([]models.SubscriberChannel, error)
+	GetSubscriberChannel(ctx context.Context, id int64) (models.SubscriberChannel, error)
+	GetChannelsByType(ctx context.Context, channelType models.ChannelType) ([]models.SubscriberChannel, error)
+	GetChannelByAddress(ctx context.Context, channelType models.ChannelType, address string) (models.SubscriberChannel, error)
+	UpdateSubscriberChannel(ctx context.Context, channel models.SubscriberChannel) error
+	DeleteSubscriberChannel(ctx context.Context, id int64) error
+
+	Subscribe(ctx context.Context, subscriberID int64, gameType models.GameType) error
+	Unsubscribe(ctx context.Context, subscriberID int64, gameType models.GameType) error
+	GetSubscriptions(ctx context.Context) ([]models.Subscription, error)
+
+	CreateTicket(ctx context.Context, ticket models.Ticket) (models.Ticket, error)
+	GetTickets(ctx context.Context, subscriberID int64) ([]models.Ticket, error)
+	GetTicketsByGame(ctx context.Context, gameType models.GameType) ([]models.Ticket, error)
+	UpdateTicket(ctx context.Context, ticket models.Ticket) error
+	DeleteTicket(ctx context.Context, id int64) error
+
+	CreateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error)
+	GetWebhooks(ctx context.Context, activeOnly bool) ([]models.Webhook, error)
+	UpdateWebhook(ctx context.Context, webhook models.Webhook) error
+	DeleteWebhook(ctx context.Context, id int64) error
+	InsertWebhookDelivery(ctx context.Context, delivery models.WebhookDelivery) error
+	GetWebhookDeliveries(ctx context.Context, webhookID int64) ([]models.WebhookDelivery, error)
+
+	GetDueOutboxMessages(ctx context.Context, now time.Time, limit int) ([]models.OutboxMessage, error)
+	GetOutboxMessages(ctx context.Context, status models.OutboxStatus) ([]models.OutboxMessage, error)
+	MarkOutboxSent(ctx context.Context, id int64) error
+	MarkOutboxFailed(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time, dead bool) error
+	RequeueOutboxMessage(ctx context.Context, id int64) error
+	ExpandOutboxMessage(ctx context.Context, id int64, targets []string) ([]models.OutboxMessage, error)
+
+	SetJackpotAlert(ctx context.Context, subscriberID int64, gameType models.GameType, threshold float64) (models.JackpotAlert, error)
+	GetJackpotAlerts(ctx context.Context, subscriberID int64) ([]models.JackpotAlert, error)
+	GetJackpotAlertsByGame(ctx context.Context, gameType models.GameType) ([]models.JackpotAlert, error)
+	UpdateJackpotAlertState(ctx context.Context, id int64, notifiedAt *time.Time, notifiedValue *float64) error
+	DeleteJackpotAlert(ctx context.Context, subscriberID int64, gameType models.GameType) error
+
+	GetJackpotTimeline(ctx context.Context, gameType models.GameType, from, to *time.Time) ([]models.GameSnapshot, error)
+	GetJackpotHits(ctx context.Context, gameType models.GameType, from, to *time.Time) ([]models.JackpotHit, error)
+
+	SetReminders(ctx context.Context, subscriberID int64, gameType models.GameType, offsets []time.Duration) error
+	GetReminders(ctx context.Context, subscriberID int64) ([]models.Reminder, error)
+	GetRemindersByGame(ctx context.Context, gameType models.GameType) ([]models.Reminder, error)
+	SchedulePendingReminder(ctx context.Context, reminderID int64, drawDate, fireAt time.Time) error
+	GetDuePendingReminders(ctx context.Context, now time.Time) ([]models.PendingReminder, error)
+	GetNextPendingReminderTime(ctx context.Context) (*time.Time, error)
+	UpdatePendingReminderStatus(ctx context.Context, id int64, status models.PendingReminderStatus) error
+	MarkPendingReminderFailed(ctx context.Context, id int64, nextAttemptAt time.Time) error
+
+	GetBackfillCheckpoint(ctx context.Context, gameType models.GameType) (models.BackfillCheckpoint, error)
+	SaveBackfillCheckpoint(ctx context.Context, checkpoint models.BackfillCheckpoint) error
+
+	SavePrizes(ctx context.Context, prizes []models.Prize) error
+	GetPrizes(ctx context.Context, gameType models.GameType, drawID uint) ([]models.Prize, error)
+	GetDuePrizeChecks(ctx context.Context, now time.Time) ([]models.PrizeCheck, error)
+	MarkPrizeCheckDone(ctx context.Context, gameType models.GameType, drawID uint) error
+	MarkPrizeCheckFailed(ctx context.Context, gameType models.GameType, drawID uint, nextCheckAt time.Time, expired bool) error
+	GetPayoutSubscribers(ctx context.Context, gameType models.GameType, drawID uint) ([]int64, error)
+	MarkPayoutNotified(ctx context.Context, gameType models.GameType, drawID uint, subscriberID int64) error
 }
 
 type repository struct {
@@ -93,6 +168,10 @@ func (r *repository) UpdateGames(ctx context.Context, games []models.Game) error
 			return fmt.Errorf("failed to execute statement: %w", err)
 		}
 	}
+	// the games row is overwritten, the snapshots keep the history
+	if err := insertGameSnapshots(ctx, trx, games, time.Now()); err != nil {
+		return fmt.Errorf("failed to insert game snapshots: %w", err)
+	}
 	err = trx.Commit()
 	if err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
@@ -100,13 +179,105 @@ func (r *repository) UpdateGames(ctx context.Context, games []models.Game) error
 	return nil
 }
 
-func (r *repository) InsertResults(ctx context.Context, results []models.Result) error {
-	stmt := `INSERT INTO results (draw_id, game_type, draw_date, prize_pool, prize_value)
-		VALUES (:draw_id, :game_type, :draw_date, :prize_pool, :prize_value)`
-	_, err := r.db.NamedExecContext(ctx, stmt, results)
+// InsertDraws saves the draws and all of their results, skipping the ones that are already stored,
+// and returns only the results that were genuinely new. Historical draws get no prize checks.
+func (r *repository) InsertDraws(ctx context.Context, draws []models.Draw) ([]models.Result, error) {
+	return r.saveDraws(ctx, "", draws, nil, false)
+}
+
+// SaveDraws inserts the draws like InsertDraws and, in the same transaction, queues an outbox
+// message with the new results for every channel, so results are notified if and only if they are stored.
+// The new results are also queued for the prizes poller.
+func (r *repository) SaveDraws(
+	ctx context.Context, gameType models.GameType, draws []models.Draw, channels []string,
+) ([]models.Result, error) {
+	return r.saveDraws(ctx, gameType, draws, channels, true)
+}
+
+func (r *repository) saveDraws(
+	ctx context.Context, gameType models.GameType, draws []models.Draw, channels []string, checkPrizes bool,
+) ([]models.Result, error) {
+	drawStmt := `INSERT INTO draws (draw_id, game_type, draw_date, multiplier_value, show_special_results,
+			is_new_eurojackpot_draw, created_at)
+		VALUES (:draw_id, :game_type, :draw_date, :multiplier_value, :show_special_results,
+			:is_new_eurojackpot_draw, :created_at)
+		ON CONFLICT (draw_id, game_type) DO NOTHING`
+	resultStmt := `INSERT INTO results (draw_id, game_type, draw_date, results, special_results, created_at,
+			parent_draw_id, parent_game_type)
+		VALUES (:draw_id, :game_type, :draw_date, :results, :special_results, :created_at,
+			:parent_draw_id, :parent_game_type)
+		ON CONFLICT (draw_id, game_type) DO NOTHING`
+
+	trx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer trx.Rollback()
+
+	// inserted one by one so we know which rows were skipped by the conflict clause
+	total := 0
+	inserted := []models.Result{}
+	for _, draw := range draws {
+		// every result of the draw was quarantined, the draw is stored once a valid result comes in
+		if len(draw.Results) == 0 {
+			continue
+		}
+		if _, err := trx.NamedExecContext(ctx, drawStmt, draw); err != nil {
+			return nil, fmt.Errorf("failed to execute statement: %w", err)
+		}
+		for _, result := range draw.Results {
+			total++
+			res, err := trx.NamedExecContext(ctx, resultStmt, result)
+			if err != nil {
+				return nil, fmt.Errorf("failed to execute statement: %w", err)
+			}
+			affected, err := res.RowsAffected()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get affected rows: %w", err)
+			}
+			if affected > 0 {
+				inserted = append(inserted, result)
+			}
+		}
+	}
+
+	if len(inserted) > 0 && len(channels) > 0 {
+		if err := enqueueOutbox(ctx, trx, gameType, inserted, channels); err != nil {
+			return nil, err
+		}
+	}
+	if checkPrizes {
+		if err := schedulePrizeChecks(ctx, trx, inserted); err != nil {
+			return nil, err
+		}
 	}
 
+	err = trx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	slog.Debug("Inserted results", "draws", len(draws), "results", total, "new", len(inserted))
+	return inserted, nil
+}
+
+// QuarantineResult keeps an invalid result aside, a result that is already quarantined is left as is
+func (r *repository) QuarantineResult(ctx context.Context, result models.QuarantinedResult) error {
+	stmt := `INSERT INTO quarantined_results (draw_id, game_type, draw_date, results, special_results, reason, created_at)
+		VALUES (:draw_id, :game_type, :draw_date, :results, :special_results, :reason, :created_at)
+		ON CONFLICT (draw_id, game_type) DO NOTHING`
+	_, err := r.db.NamedExecContext(ctx, stmt, result)
+	if err != nil {
+		return err
+	}
 	return nil
 }
+
+func (r *repository) GetQuarantinedResults(ctx context.Context, gameType string) ([]models.QuarantinedResult, error) {
+	stmt := `SELECT * FROM quarantined_results WHERE game_type = ? ORDER BY draw_date DESC`
+	results := []models.QuarantinedResult{}
+	err := r.db.SelectContext(ctx, &results, stmt, gameType)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
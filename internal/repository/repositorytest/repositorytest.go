@@ -0,0 +1,27 @@
+// Package repositorytest provides repositories for tests, backed by a real migrated SQLite database
+package repositorytest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"lotto-notifications/internal/database"
+	"lotto-notifications/internal/repository"
+)
+
+// New returns a repository on a fresh database in the test's temporary directory,
+// the database is closed when the test finishes
+func New(t testing.TB) repository.Repository {
+	t.Helper()
+
+	if err := database.Initialize(filepath.Join(t.TempDir(), "test.sqlite")); err != nil {
+		t.Fatalf("failed to initialize database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	db, err := database.GetDB()
+	if err != nil {
+		t.Fatalf("failed to get database: %v", err)
+	}
+	return repository.NewRepository(db)
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"lotto-notifications/internal/models"
+)
+
+// ResultsQuery filters and pages results, newest draws first.
+// AfterDrawID is the keyset cursor: only draws with a lower ID are returned.
+type ResultsQuery struct {
+	From        *time.Time
+	To          *time.Time
+	AfterDrawID *uint
+	Limit       int
+}
+
+func (r *repository) GetResultsPage(ctx context.Context, gameType string, query ResultsQuery) ([]models.Result, error) {
+	conditions := []string{"game_type = ?"}
+	args := []any{gameType}
+	// dates are compared as stored text, so they have to be bound in UTC like the API returns them
+	if query.From != nil {
+		conditions = append(conditions, "draw_date >= ?")
+		args = append(args, query.From.UTC())
+	}
+	if query.To != nil {
+		conditions = append(conditions, "draw_date <= ?")
+		args = append(args, query.To.UTC())
+	}
+	if query.AfterDrawID != nil {
+		conditions = append(conditions, "draw_id < ?")
+		args = append(args, *query.AfterDrawID)
+	}
+	args = append(args, query.Limit)
+
+	stmt := `SELECT * FROM results WHERE ` + strings.Join(conditions, " AND ") + ` ORDER BY draw_id DESC LIMIT ?`
+	results := []models.Result{}
+	err := r.db.SelectContext(ctx, &results, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *repository) GetResult(ctx context.Context, gameType models.GameType, drawID uint) (models.Result, error) {
+	stmt := `SELECT * FROM results WHERE game_type = ? AND draw_id = ?`
+	result := models.Result{}
+	err := r.db.GetContext(ctx, &result, stmt, gameType, drawID)
+	if err != nil {
+		return models.Result{}, err
+	}
+	return result, nil
+}
+
+// GetDraw returns a draw with all of the results that came in it
+func (r *repository) GetDraw(ctx context.Context, gameType models.GameType, drawID uint) (models.Draw, error) {
+	draw := models.Draw{}
+	err := r.db.GetContext(ctx, &draw, `SELECT * FROM draws WHERE game_type = ? AND draw_id = ?`, gameType, drawID)
+	if err != nil {
+		return models.Draw{}, err
+	}
+
+	stmt := `SELECT * FROM results WHERE parent_game_type = ? AND parent_draw_id = ? ORDER BY game_type`
+	draw.Results = []models.Result{}
+	err = r.db.SelectContext(ctx, &draw.Results, stmt, gameType, drawID)
+	if err != nil {
+		return models.Draw{}, fmt.Errorf("failed to get draw results: %w", err)
+	}
+	return draw, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"lotto-notifications/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// insertGameSnapshots stores fetchedAt in UTC, SQLite compares the timestamps as text
+// so every fetched_at and every bound time has to be in the same zone
+func insertGameSnapshots(ctx context.Context, trx *sqlx.Tx, games []models.Game, fetchedAt time.Time) error {
+	stmt := `INSERT INTO game_snapshots
+		(game_type, next_draw_date, closest_prize_value, closest_prize_pool, coupon_price, fetched_at)
+		VALUES (:game_type, :next_draw_date, :closest_prize_value, :closest_prize_pool, :coupon_price, :fetched_at)`
+	for _, game := range games {
+		snapshot := models.GameSnapshot{
+			GameType:          game.GameType,
+			NextDrawDate:      game.NextDrawDate,
+			ClosestPrizeValue: game.ClosestPrizeValue,
+			ClosestPrizePool:  game.ClosestPrizePool,
+			CouponPrice:       game.CouponPrice,
+			FetchedAt:         fetchedAt.UTC(),
+		}
+		if _, err := trx.NamedExecContext(ctx, stmt, snapshot); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetJackpotTimeline returns the snapshots of a game in which the jackpot or the next draw changed,
+// oldest first. The first snapshot in the range is always included so the timeline has a starting point.
+func (r *repository) GetJackpotTimeline(
+	ctx context.Context, gameType models.GameType, from, to *time.Time,
+) ([]models.GameSnapshot, error) {
+	stmt := `SELECT id, game_type, next_draw_date, closest_prize_value, closest_prize_pool, coupon_price, fetched_at
+	FROM (
+		SELECT *,
+			LAG(id) OVER w AS prev_id,
+			LAG(closest_prize_value) OVER w AS prev_value,
+			LAG(next_draw_date) OVER w AS prev_next_draw_date
+		FROM game_snapshots
+		WHERE game_type = ? AND (? IS NULL OR fetched_at >= ?) AND (? IS NULL OR fetched_at <= ?)
+		WINDOW w AS (ORDER BY fetched_at, id)
+	)
+	WHERE prev_id IS NULL
+		OR closest_prize_value IS NOT prev_value
+		OR next_draw_date IS NOT prev_next_draw_date
+	ORDER BY fetched_at, id`
+
+	from, to = utc(from), utc(to)
+	snapshots := []models.GameSnapshot{}
+	err := r.db.SelectContext(ctx, &snapshots, stmt, gameType, from, from, to, to)
+	if err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	converted := t.UTC()
+	return &converted
+}
+
+// GetJackpotHits detects the draws in which the jackpot was won from the drops in the jackpot timeline
+func (r *repository) GetJackpotHits(
+	ctx context.Context, gameType models.GameType, from, to *time.Time,
+) ([]models.JackpotHit, error) {
+	timeline, err := r.GetJackpotTimeline(ctx, gameType, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := []models.JackpotHit{}
+	var previous *models.GameSnapshot
+	for idx := range timeline {
+		snapshot := &timeline[idx]
+		if snapshot.ClosestPrizeValue == nil {
+			continue
+		}
+		if previous != nil && *snapshot.ClosestPrizeValue < *previous.ClosestPrizeValue {
+			hits = append(hits, models.JackpotHit{
+				GameType:    gameType,
+				DrawDate:    previous.NextDrawDate,
+				Jackpot:     *previous.ClosestPrizeValue,
+				NextJackpot: *snapshot.ClosestPrizeValue,
+				DetectedAt:  snapshot.FetchedAt,
+			})
+		}
+		previous = snapshot
+	}
+	return hits, nil
+}
@@ -0,0 +1,71 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/repository/repositorytest"
+)
+
+func TestGetJackpotTimelineIgnoresTimeZones(t *testing.T) {
+	ctx := context.Background()
+	repo := repositorytest.New(t)
+
+	for _, jackpot := range []float64{2_000_000, 4_000_000} {
+		game := models.Game{GameType: models.GameTypeLotto, ClosestPrizeValue: &jackpot}
+		if err := repo.UpdateGames(ctx, []models.Game{game}); err != nil {
+			t.Fatalf("UpdateGames() error = %v", err)
+		}
+	}
+
+	now := time.Now()
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     int
+	}{
+		{
+			name: "range in UTC",
+			from: now.Add(-time.Minute).UTC(),
+			to:   now.Add(time.Minute).UTC(),
+			want: 2,
+		},
+		{
+			name: "range east of UTC",
+			from: now.Add(-time.Minute).In(time.FixedZone("UTC+14", 14*60*60)),
+			to:   now.Add(time.Minute).In(time.FixedZone("UTC+14", 14*60*60)),
+			want: 2,
+		},
+		{
+			name: "range west of UTC",
+			from: now.Add(-time.Minute).In(time.FixedZone("UTC-10", -10*60*60)),
+			to:   now.Add(time.Minute).In(time.FixedZone("UTC-10", -10*60*60)),
+			want: 2,
+		},
+		{
+			name: "range before the snapshots",
+			from: now.Add(-2 * time.Hour).In(time.FixedZone("UTC+14", 14*60*60)),
+			to:   now.Add(-time.Hour).In(time.FixedZone("UTC+14", 14*60*60)),
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeline, err := repo.GetJackpotTimeline(ctx, models.GameTypeLotto, &tt.from, &tt.to)
+			if err != nil {
+				t.Fatalf("GetJackpotTimeline() error = %v", err)
+			}
+			if len(timeline) != tt.want {
+				t.Fatalf("GetJackpotTimeline() returned %d snapshots, want %d", len(timeline), tt.want)
+			}
+			for _, snapshot := range timeline {
+				if snapshot.FetchedAt.Location() != time.UTC {
+					t.Errorf("fetched_at = %v, want it in UTC", snapshot.FetchedAt)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"lotto-notifications/internal/models"
+)
+
+func (r *repository) CreateSubscriber(ctx context.Context, subscriber models.Subscriber) (models.Subscriber, error) {
+	stmt := `INSERT INTO subscribers (name, created_at) VALUES (:name, :created_at)`
+	if subscriber.CreatedAt.IsZero() {
+		subscriber.CreatedAt = time.Now()
+	}
+	res, err := r.db.NamedExecContext(ctx, stmt, subscriber)
+	if err != nil {
+		return models.Subscriber{}, err
+	}
+	subscriber.ID, err = res.LastInsertId()
+	if err != nil {
+		return models.Subscriber{}, fmt.Errorf("failed to get subscriber id: %w", err)
+	}
+	return subscriber, nil
+}
+
+func (r *repository) GetSubscribers(ctx context.Context) ([]models.Subscriber, error) {
+	stmt := `SELECT * FROM subscribers ORDER BY id`
+	subscribers := []models.Subscriber{}
+	err := r.db.SelectContext(ctx, &subscribers, stmt)
+	if err != nil {
+		return nil, err
+	}
+	return subscribers, nil
+}
+
+func (r *repository) GetSubscriber(ctx context.Context, id int64) (models.Subscriber, error) {
+	stmt := `SELECT * FROM subscribers WHERE id = ?`
+	subscriber := models.Subscriber{}
+	err := r.db.GetContext(ctx, &subscriber, stmt, id)
+	if err != nil {
+		return models.Subscriber{}, err
+	}
+	return subscriber, nil
+}
+
+func (r *repository) UpdateSubscriber(ctx context.Context, subscriber models.Subscriber) error {
+	stmt := `UPDATE subscribers SET name = :name WHERE id = :id`
+	res, err := r.db.NamedExecContext(ctx, stmt, subscriber)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res)
+}
+
+// DeleteSubscriber removes the subscriber together with their channels, tickets, subscriptions,
+// alerts, reminders and payout notifications
+func (r *repository) DeleteSubscriber(ctx context.Context, id int64) error {
+	trx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer trx.Rollback()
+
+	for _, stmt := range []string{
+		`DELETE FROM subscriber_channels WHERE subscriber_id = ?`,
+		`DELETE FROM tickets WHERE subscriber_id = ?`,
+		`DELETE FROM subscriptions WHERE subscriber_id = ?`,
+		`DELETE FROM jackpot_alerts WHERE subscriber_id = ?`,
+		`DELETE FROM pending_reminders WHERE reminder_id IN (SELECT id FROM reminders WHERE subscriber_id = ?)`,
+		`DELETE FROM reminders WHERE subscriber_id = ?`,
+		`DELETE FROM payout_notifications WHERE subscriber_id = ?`,
+	} {
+		if _, err := trx.ExecContext(ctx, stmt, id); err != nil {
+			return fmt.Errorf("failed to execute statement: %w", err)
+		}
+	}
+
+	res, err := trx.ExecContext(ctx, `DELETE FROM subscribers WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to execute statement: %w", err)
+	}
+	if err := expectAffected(res); err != nil {
+		return err
+	}
+
+	err = trx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *repository) AddSubscriberChannel(
+	ctx context.Context, channel models.SubscriberChannel,
+) (models.SubscriberChannel, error) {
+	stmt := `INSERT INTO subscriber_channels (subscriber_id, type, address, token, created_at)
+		VALUES (:subscriber_id, :type, :address, :token, :created_at)`
+	if channel.CreatedAt.IsZero() {
+		channel.CreatedAt = time.Now()
+	}
+	res, err := r.db.NamedExecContext(ctx, stmt, channel)
+	if err != nil {
+		return models.SubscriberChannel{}, err
+	}
+	channel.ID, err = res.LastInsertId()
+	if err != nil {
+		return models.SubscriberChannel{}, fmt.Errorf("failed to get channel id: %w", err)
+	}
+	return channel, nil
+}
+
+func (r *repository) GetSubscriberChannels(ctx context.Context, subscriberID int64) ([]models.SubscriberChannel, error) {
+	stmt := `SELECT * FROM subscriber_channels WHERE subscriber_id = ? ORDER BY id`
+	channels := []models.SubscriberChannel{}
+	err := r.db.SelectContext(ctx, &channels, stmt, subscriberID)
+	if err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+func (r *repository) GetSubscriberChannel(ctx context.Context, id int64) (models.SubscriberChannel, error) {
+	stmt := `SELECT * FROM subscriber_channels WHERE id = ?`
+	channel := models.SubscriberChannel{}
+	err := r.db.GetContext(ctx, &channel, stmt, id)
+	if err != nil {
+		return models.SubscriberChannel{}, err
+	}
+	return channel, nil
+}
+
+func (r *repository) GetChannelsByType(
+	ctx context.Context, channelType models.ChannelType,
+) ([]models.SubscriberChannel, error) {
+	stmt := `SELECT * FROM subscriber_channels WHERE type = ? ORDER BY id`
+	channels := []models.SubscriberChannel{}
+	err := r.db.SelectContext(ctx, &channels, stmt, channelType)
+	if err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+func (r *repository) UpdateSubscriberChannel(ctx context.Context, channel models.SubscriberChannel) error {
+	stmt := `UPDATE subscriber_channels SET
+		type = :type,
+		address = :address,
+		token = :token
+	WHERE id = :id`
+	res, err := r.db.NamedExecContext(ctx, stmt, channel)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res)
+}
+
+func (r *repository) DeleteSubscriberChannel(ctx context.Context, id int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM subscriber_channels WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res)
+}
+
+// expectAffected turns an update or delete that matched no rows into ErrNotFound
+func expectAffected(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *repository) GetChannelByAddress(
+	ctx context.Context, channelType models.ChannelType, address string,
+) (models.SubscriberChannel, error) {
+	stmt := `SELECT * FROM subscriber_channels WHERE type = ? AND address = ? ORDER BY id LIMIT 1`
+	channel := models.SubscriberChannel{}
+	err := r.db.GetContext(ctx, &channel, stmt, channelType, address)
+	if err != nil {
+		return models.SubscriberChannel{}, err
+	}
+	return channel, nil
+}
+
+// Subscribe opts the subscriber in to a game, subscribing twice is not an error
+func (r *repository) Subscribe(ctx context.Context, subscriberID int64, gameType models.GameType) error {
+	stmt := `INSERT INTO subscriptions (subscriber_id, game_type, created_at) VALUES (?, ?, ?)
+		ON CONFLICT (subscriber_id, game_type) DO NOTHING`
+	_, err := r.db.ExecContext(ctx, stmt, subscriberID, gameType, time.Now())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *repository) Unsubscribe(ctx context.Context, subscriberID int64, gameType models.GameType) error {
+	stmt := `DELETE FROM subscriptions WHERE subscriber_id = ? AND game_type = ?`
+	res, err := r.db.ExecContext(ctx, stmt, subscriberID, gameType)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res)
+}
+
+func (r *repository) GetSubscriptions(ctx context.Context) ([]models.Subscription, error) {
+	stmt := `SELECT * FROM subscriptions ORDER BY subscriber_id, game_type`
+	subscriptions := []models.Subscription{}
+	err := r.db.SelectContext(ctx, &subscriptions, stmt)
+	if err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
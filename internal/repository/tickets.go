@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lotto-notifications/internal/models"
+)
+
+func (r *repository) CreateTicket(ctx context.Context, ticket models.Ticket) (models.Ticket, error) {
+	stmt := `INSERT INTO tickets (subscriber_id, game_type, numbers, special_numbers, created_at)
+		VALUES (:subscriber_id, :game_type, :numbers, :special_numbers, :created_at)`
+	if ticket.CreatedAt.IsZero() {
+		ticket.CreatedAt = time.Now()
+	}
+	res, err := r.db.NamedExecContext(ctx, stmt, ticket)
+	if err != nil {
+		return models.Ticket{}, err
+	}
+	ticket.ID, err = res.LastInsertId()
+	if err != nil {
+		return models.Ticket{}, fmt.Errorf("failed to get ticket id: %w", err)
+	}
+	return ticket, nil
+}
+
+func (r *repository) GetTickets(ctx context.Context, subscriberID int64) ([]models.Ticket, error) {
+	stmt := `SELECT * FROM tickets WHERE subscriber_id = ? ORDER BY id`
+	tickets := []models.Ticket{}
+	err := r.db.SelectContext(ctx, &tickets, stmt, subscriberID)
+	if err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+func (r *repository) GetTicketsByGame(ctx context.Context, gameType models.GameType) ([]models.Ticket, error) {
+	stmt := `SELECT * FROM tickets WHERE game_type = ? ORDER BY id`
+	tickets := []models.Ticket{}
+	err := r.db.SelectContext(ctx, &tickets, stmt, gameType)
+	if err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+func (r *repository) UpdateTicket(ctx context.Context, ticket models.Ticket) error {
+	stmt := `UPDATE tickets SET
+		game_type = :game_type,
+		numbers = :numbers,
+		special_numbers = :special_numbers
+	WHERE id = :id`
+	res, err := r.db.NamedExecContext(ctx, stmt, ticket)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res)
+}
+
+func (r *repository) DeleteTicket(ctx context.Context, id int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM tickets WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res)
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lotto-notifications/internal/models"
+)
+
+func (r *repository) CreateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error) {
+	stmt := `INSERT INTO webhooks (url, secret, active, created_at) VALUES (:url, :secret, :active, :created_at)`
+	if webhook.CreatedAt.IsZero() {
+		webhook.CreatedAt = time.Now()
+	}
+	res, err := r.db.NamedExecContext(ctx, stmt, webhook)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+	webhook.ID, err = res.LastInsertId()
+	if err != nil {
+		return models.Webhook{}, fmt.Errorf("failed to get webhook id: %w", err)
+	}
+	return webhook, nil
+}
+
+func (r *repository) GetWebhooks(ctx context.Context, activeOnly bool) ([]models.Webhook, error) {
+	stmt := `SELECT * FROM webhooks`
+	if activeOnly {
+		stmt += ` WHERE active = TRUE`
+	}
+	stmt += ` ORDER BY id`
+	webhooks := []models.Webhook{}
+	err := r.db.SelectContext(ctx, &webhooks, stmt)
+	if err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (r *repository) UpdateWebhook(ctx context.Context, webhook models.Webhook) error {
+	stmt := `UPDATE webhooks SET
+		url = :url,
+		secret = :secret,
+		active = :active
+	WHERE id = :id`
+	res, err := r.db.NamedExecContext(ctx, stmt, webhook)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res)
+}
+
+// DeleteWebhook removes the webhook together with its delivery log
+func (r *repository) DeleteWebhook(ctx context.Context, id int64) error {
+	trx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer trx.Rollback()
+
+	if _, err := trx.ExecContext(ctx, `DELETE FROM webhook_deliveries WHERE webhook_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to execute statement: %w", err)
+	}
+	res, err := trx.ExecContext(ctx, `DELETE FROM webhooks WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to execute statement: %w", err)
+	}
+	if err := expectAffected(res); err != nil {
+		return err
+	}
+
+	err = trx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *repository) InsertWebhookDelivery(ctx context.Context, delivery models.WebhookDelivery) error {
+	stmt := `INSERT INTO webhook_deliveries (webhook_id, delivery_id, status_code, error, duration_ms, created_at)
+		VALUES (:webhook_id, :delivery_id, :status_code, :error, :duration_ms, :created_at)`
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = time.Now()
+	}
+	_, err := r.db.NamedExecContext(ctx, stmt, delivery)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *repository) GetWebhookDeliveries(ctx context.Context, webhookID int64) ([]models.WebhookDelivery, error) {
+	stmt := `SELECT * FROM webhook_deliveries WHERE webhook_id = ? ORDER BY id DESC`
+	deliveries := []models.WebhookDelivery{}
+	err := r.db.SelectContext(ctx, &deliveries, stmt, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
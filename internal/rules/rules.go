@@ -0,0 +1,119 @@
+package rules
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"lotto-notifications/internal/models"
+)
+
+// Pool describes a set of numbers drawn from 1 to Size
+type Pool struct {
+	Size  int
+	Drawn int
+	// MinPick and MaxPick are how many numbers a player picks from the pool
+	MinPick int
+	MaxPick int
+	// Optional pools may be missing from a result, the API does not always return them
+	Optional bool
+}
+
+// Game describes how a game is drawn and played
+type Game struct {
+	GameType models.GameType
+	Main     Pool
+	// Special is nil when the game has no special numbers
+	Special  *Pool
+	Schedule Schedule
+	// TiedTo is the game this one is drawn together with, mirrors games.tied_to
+	TiedTo *models.GameType
+}
+
+func pool(size, drawn int) Pool {
+	return Pool{Size: size, Drawn: drawn, MinPick: drawn, MaxPick: drawn}
+}
+
+func tiedTo(gameType models.GameType) *models.GameType {
+	return &gameType
+}
+
+var lottoSchedule = Schedule{
+	Weekdays: []time.Weekday{time.Tuesday, time.Thursday, time.Saturday},
+	Times:    []Clock{{22, 0}},
+}
+
+var registry = map[models.GameType]Game{
+	models.GameTypeLotto: {
+		GameType: models.GameTypeLotto,
+		Main:     pool(49, 6),
+		Schedule: lottoSchedule,
+	},
+	models.GameTypeLottoPlus: {
+		GameType: models.GameTypeLottoPlus,
+		Main:     pool(49, 6),
+		Schedule: lottoSchedule,
+		TiedTo:   tiedTo(models.GameTypeLotto),
+	},
+	models.GameTypeEuroJackpot: {
+		GameType: models.GameTypeEuroJackpot,
+		Main:     pool(50, 5),
+		Special:  &Pool{Size: 12, Drawn: 2, MinPick: 2, MaxPick: 2},
+		Schedule: Schedule{
+			Weekdays: []time.Weekday{time.Tuesday, time.Friday},
+			Times:    []Clock{{20, 0}},
+		},
+	},
+	models.GameTypeMultiMulti: {
+		GameType: models.GameTypeMultiMulti,
+		Main:     Pool{Size: 80, Drawn: 20, MinPick: 1, MaxPick: 10},
+		// the Plus number is drawn from the main numbers, players do not pick it
+		Special: &Pool{Size: 80, Drawn: 1, Optional: true},
+		Schedule: Schedule{
+			Times: []Clock{{22, 0}, {14, 0}},
+		},
+	},
+	models.GameTypeMiniLotto: {
+		GameType: models.GameTypeMiniLotto,
+		Main:     pool(42, 5),
+		Schedule: Schedule{
+			Times: []Clock{{22, 0}},
+		},
+	},
+	models.GameTypeKaskada: {
+		GameType: models.GameTypeKaskada,
+		Main:     pool(24, 12),
+		Schedule: Schedule{
+			Weekdays: []time.Weekday{
+				time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday,
+			},
+			Times: []Clock{{22, 0}},
+		},
+	},
+	models.GameTypeEkstraPensja: {
+		GameType: models.GameTypeEkstraPensja,
+		Main:     pool(35, 5),
+		Special:  &Pool{Size: 4, Drawn: 1, MinPick: 1, MaxPick: 1},
+		Schedule: Schedule{
+			Times: []Clock{{22, 0}},
+		},
+	},
+}
+
+// Get returns the rules of the game, ok is false for games that are not described
+func Get(gameType models.GameType) (Game, bool) {
+	game, ok := registry[gameType]
+	return game, ok
+}
+
+// All returns the rules of every described game, ordered by game type
+func All() []Game {
+	games := make([]Game, 0, len(registry))
+	for _, game := range registry {
+		games = append(games, game)
+	}
+	slices.SortFunc(games, func(a, b Game) int {
+		return strings.Compare(string(a.GameType), string(b.GameType))
+	})
+	return games
+}
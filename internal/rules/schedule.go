@@ -0,0 +1,69 @@
+package rules
+
+import "time"
+
+var warsaw = loadWarsaw()
+
+// Location returns the time zone the draws are scheduled in
+func Location() *time.Location {
+	return warsaw
+}
+
+func loadWarsaw() *time.Location {
+	loc, err := time.LoadLocation("Europe/Warsaw")
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// Clock is a time of day
+type Clock struct {
+	Hour   int
+	Minute int
+}
+
+// Schedule describes when the draws of a game take place, in Polish time
+type Schedule struct {
+	Weekdays []time.Weekday // empty means every day
+	Times    []Clock        // sorted from the latest to the earliest
+}
+
+func (s Schedule) drawsOn(weekday time.Weekday) bool {
+	if len(s.Weekdays) == 0 {
+		return true
+	}
+	for _, w := range s.Weekdays {
+		if w == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// PreviousDraw returns the latest scheduled draw at or before t
+func (s Schedule) PreviousDraw(t time.Time) time.Time {
+	t = t.In(warsaw)
+	for days := 0; days <= 7; days++ {
+		day := t.AddDate(0, 0, -days)
+		if !s.drawsOn(day.Weekday()) {
+			continue
+		}
+		for _, c := range s.Times {
+			draw := time.Date(day.Year(), day.Month(), day.Day(), c.Hour, c.Minute, 0, 0, warsaw)
+			if !draw.After(t) {
+				return draw
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// DrawsBetween counts the scheduled draws in the (from, to] range
+func (s Schedule) DrawsBetween(from, to time.Time) int {
+	count := 0
+	for draw := s.PreviousDraw(to); draw.After(from); draw = s.PreviousDraw(draw.Add(-time.Minute)) {
+		count++
+	}
+	return count
+}
@@ -0,0 +1,57 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+
+	"lotto-notifications/internal/models"
+)
+
+var (
+	ErrUnknownGame   = errors.New("game is not described by the rules registry")
+	ErrInvalidResult = errors.New("invalid result")
+)
+
+// ValidateResult checks that a draw result could have been drawn in its game:
+// the count of numbers matches, every number is in range and none repeats
+func ValidateResult(result models.Result) error {
+	game, ok := Get(result.GameType)
+	if !ok {
+		return ErrUnknownGame
+	}
+
+	if err := validateDrawn(result.Results, game.Main); err != nil {
+		return fmt.Errorf("%w: results: %w", ErrInvalidResult, err)
+	}
+
+	if game.Special == nil {
+		if len(result.SpecialResults) > 0 {
+			return fmt.Errorf("%w: %s has no special numbers", ErrInvalidResult, result.GameType)
+		}
+		return nil
+	}
+	if err := validateDrawn(result.SpecialResults, *game.Special); err != nil {
+		return fmt.Errorf("%w: special results: %w", ErrInvalidResult, err)
+	}
+	return nil
+}
+
+func validateDrawn(drawn []int, pool Pool) error {
+	if pool.Optional && len(drawn) == 0 {
+		return nil
+	}
+	if len(drawn) != pool.Drawn {
+		return fmt.Errorf("expected %d numbers, got %d", pool.Drawn, len(drawn))
+	}
+	seen := make(map[int]struct{}, len(drawn))
+	for _, num := range drawn {
+		if num < 1 || num > pool.Size {
+			return fmt.Errorf("number %d is out of range 1-%d", num, pool.Size)
+		}
+		if _, ok := seen[num]; ok {
+			return fmt.Errorf("number %d is drawn twice", num)
+		}
+		seen[num] = struct{}{}
+	}
+	return nil
+}
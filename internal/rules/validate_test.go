@@ -0,0 +1,84 @@
+package rules
+
+import (
+	"errors"
+	"testing"
+
+	"lotto-notifications/internal/models"
+)
+
+func seq(first, last int) []int {
+	nums := make([]int, 0, last-first+1)
+	for num := first; num <= last; num++ {
+		nums = append(nums, num)
+	}
+	return nums
+}
+
+func TestValidateResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  models.Result
+		wantErr error
+	}{
+		{
+			name:   "Lotto",
+			result: models.Result{GameType: models.GameTypeLotto, Results: seq(1, 6)},
+		},
+		{
+			name:    "Lotto with too few numbers",
+			result:  models.Result{GameType: models.GameTypeLotto, Results: seq(1, 5)},
+			wantErr: ErrInvalidResult,
+		},
+		{
+			name:    "Lotto with a number out of range",
+			result:  models.Result{GameType: models.GameTypeLotto, Results: []int{1, 2, 3, 4, 5, 50}},
+			wantErr: ErrInvalidResult,
+		},
+		{
+			name:    "Lotto with a number drawn twice",
+			result:  models.Result{GameType: models.GameTypeLotto, Results: []int{1, 2, 3, 4, 5, 5}},
+			wantErr: ErrInvalidResult,
+		},
+		{
+			name:    "Lotto with special numbers",
+			result:  models.Result{GameType: models.GameTypeLotto, Results: seq(1, 6), SpecialResults: []int{1}},
+			wantErr: ErrInvalidResult,
+		},
+		{
+			name:   "EuroJackpot",
+			result: models.Result{GameType: models.GameTypeEuroJackpot, Results: seq(1, 5), SpecialResults: []int{1, 2}},
+		},
+		{
+			name:    "EuroJackpot without special numbers",
+			result:  models.Result{GameType: models.GameTypeEuroJackpot, Results: seq(1, 5)},
+			wantErr: ErrInvalidResult,
+		},
+		{
+			name:   "MultiMulti with the Plus number",
+			result: models.Result{GameType: models.GameTypeMultiMulti, Results: seq(1, 20), SpecialResults: []int{7}},
+		},
+		{
+			name:   "MultiMulti without the Plus number",
+			result: models.Result{GameType: models.GameTypeMultiMulti, Results: seq(1, 20)},
+		},
+		{
+			name:    "MultiMulti with two Plus numbers",
+			result:  models.Result{GameType: models.GameTypeMultiMulti, Results: seq(1, 20), SpecialResults: []int{7, 8}},
+			wantErr: ErrInvalidResult,
+		},
+		{
+			name:    "unknown game",
+			result:  models.Result{GameType: "Keno", Results: seq(1, 20)},
+			wantErr: ErrUnknownGame,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateResult(tt.result); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateResult() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
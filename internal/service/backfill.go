@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/pkg/lotto"
+)
+
+const backfillPageSize = 50
+
+// BackfillResults walks the game's history page by page, oldest first. Progress is checkpointed after
+// every page, so running it again with the same from date resumes an interrupted backfill.
+// Backfilled results are not notified, their prizes are stored before the page is checkpointed.
+func (s *service) BackfillResults(ctx context.Context, gameType models.GameType, from, to time.Time) (int, error) {
+	checkpoint, err := s.repo.GetBackfillCheckpoint(ctx, gameType)
+	switch {
+	case err == nil && checkpoint.From.Equal(from) && checkpoint.CompletedAt == nil:
+		// the range is kept from the first run so the pages do not shift
+		slog.Info("Resuming backfill", "game", gameType, "page", checkpoint.NextPage, "inserted", checkpoint.Inserted)
+	case err == nil || errors.Is(err, sql.ErrNoRows):
+		checkpoint = models.BackfillCheckpoint{
+			GameType: gameType,
+			From:     from,
+			To:       to,
+			NextPage: 1,
+		}
+	default:
+		return 0, fmt.Errorf("failed to get backfill checkpoint: %w", err)
+	}
+
+	for {
+		page, err := s.lottoClient.GetDrawResults(ctx, lotto.DrawsQuery{
+			GameType: string(gameType),
+			From:     checkpoint.From,
+			To:       checkpoint.To,
+			Page:     checkpoint.NextPage,
+			Size:     backfillPageSize,
+		})
+		if err != nil {
+			return checkpoint.Inserted, fmt.Errorf("failed to get draw results page %d: %w", checkpoint.NextPage, err)
+		}
+
+		inserted, results, err := s.insertDraws(ctx, page.Items)
+		if err != nil {
+			return checkpoint.Inserted, err
+		}
+		if err := s.updateMissingPrizes(ctx, results); err != nil {
+			return checkpoint.Inserted, err
+		}
+
+		checkpoint.Inserted += inserted
+		checkpoint.NextPage++
+		done := len(page.Items) < backfillPageSize || (checkpoint.NextPage-1)*backfillPageSize >= page.TotalRows
+		if done {
+			now := time.Now()
+			checkpoint.CompletedAt = &now
+		}
+		if err := s.repo.SaveBackfillCheckpoint(ctx, checkpoint); err != nil {
+			return checkpoint.Inserted, fmt.Errorf("failed to save backfill checkpoint: %w", err)
+		}
+
+		slog.Info("Backfilled page",
+			"game", gameType,
+			"page", checkpoint.NextPage-1,
+			"draws", len(page.Items),
+			"new", inserted,
+			"totalRows", page.TotalRows,
+		)
+		if done {
+			return checkpoint.Inserted, nil
+		}
+	}
+}
+
+func (s *service) BackfillDraw(ctx context.Context, gameType models.GameType, drawID uint) (int, error) {
+	draws, err := s.lottoClient.GetDrawResultsByID(ctx, string(gameType), drawID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get draw results: %w", err)
+	}
+	if len(draws) == 0 {
+		return 0, ErrNoResultsAvailable
+	}
+
+	inserted, results, err := s.insertDraws(ctx, draws)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.UpdatePrizes(ctx, results); err != nil {
+		return inserted, err
+	}
+	return inserted, nil
+}
+
+// insertDraws validates and stores historical draws, draws without results are skipped.
+// It returns how many results were new and every valid result, stored before or not.
+func (s *service) insertDraws(ctx context.Context, lottoDraws []lotto.Draw) (int, []models.Result, error) {
+	draws := make([]models.Draw, 0, len(lottoDraws))
+	var results []models.Result
+	for _, lottoDraw := range lottoDraws {
+		draw, err := drawFromLotto(lottoDraw)
+		if errors.Is(err, ErrNoResultsInDraw) {
+			slog.Warn("Skipping draw without results", "game", lottoDraw.GameType, "drawID", lottoDraw.DrawSystemID)
+			continue
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+
+		draw, err = s.validateDraw(ctx, draw)
+		if err != nil {
+			return 0, nil, err
+		}
+		draws = append(draws, draw)
+		results = append(results, draw.Results...)
+	}
+
+	inserted, err := s.repo.InsertDraws(ctx, draws)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to insert draws: %w", err)
+	}
+	return len(inserted), results, nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/pkg/lotto"
+)
+
+// UpdatePrizes fetches and stores the prize breakdowns of the results' draws
+func (s *service) UpdatePrizes(ctx context.Context, results []models.Result) error {
+	for _, result := range results {
+		draws, err := s.lottoClient.GetDrawPrizes(ctx, string(result.GameType), result.DrawID)
+		if err != nil {
+			return fmt.Errorf("failed to get draw prizes: %w", err)
+		}
+
+		var prizes []models.Prize
+		for _, draw := range draws {
+			prizes = append(prizes, prizesFromDraw(draw)...)
+		}
+		if len(prizes) == 0 {
+			slog.Debug("Prizes not published yet", "game", result.GameType, "drawID", result.DrawID)
+			continue
+		}
+		if err := s.repo.SavePrizes(ctx, prizes); err != nil {
+			return fmt.Errorf("failed to save prizes: %w", err)
+		}
+	}
+	return nil
+}
+
+// updateMissingPrizes fetches the prizes of the results that have none stored yet
+func (s *service) updateMissingPrizes(ctx context.Context, results []models.Result) error {
+	var missing []models.Result
+	for _, result := range results {
+		prizes, err := s.repo.GetPrizes(ctx, result.GameType, result.DrawID)
+		if err != nil {
+			return fmt.Errorf("failed to get prizes: %w", err)
+		}
+		if len(prizes) == 0 {
+			missing = append(missing, result)
+		}
+	}
+	return s.UpdatePrizes(ctx, missing)
+}
+
+func prizesFromDraw(draw lotto.DrawPrizes) []models.Prize {
+	prizes := make([]models.Prize, 0, len(draw.Prizes))
+	for key, prize := range draw.Prizes {
+		tier, err := strconv.Atoi(key)
+		if err != nil || tier < 1 {
+			slog.Warn("Skipping prize with unknown tier", "game", draw.GameType, "drawID", draw.DrawSystemID, "tier", key)
+			continue
+		}
+		prizes = append(prizes, models.Prize{
+			DrawID:   draw.DrawSystemID,
+			GameType: models.GameType(draw.GameType),
+			Tier:     tier,
+			Winners:  prize.Winners,
+			Amount:   prize.PrizeValue,
+		})
+	}
+	return prizes
+}
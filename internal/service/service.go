@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
 	"time"
 
 	"lotto-notifications/internal/models"
 	"lotto-notifications/internal/repository"
+	"lotto-notifications/internal/rules"
 	"lotto-notifications/pkg/lotto"
 )
 
@@ -16,26 +19,46 @@ var (
 	ErrNoResultsInDraw        = errors.New("no results in draw")
 	ErrResultsNotYetAvailable = errors.New("results not yet available")
 	ErrMainGameNotFound       = errors.New("main game not found")
+	ErrMainResultInvalid      = errors.New("result of the main game is invalid")
 )
 
 type Service interface {
 	UpdateAllGames(ctx context.Context) ([]models.Game, error)
 	UpdateGame(ctx context.Context, gameType models.GameType) (models.Game, error)
+	// GetAndSaveNewestResults returns only the results that were not saved before
 	GetAndSaveNewestResults(ctx context.Context, gameType models.GameType, nextDrawDate time.Time) ([]models.Result, error)
+	// BackfillResults stores the missing draws held between from and to and returns how many were new
+	BackfillResults(ctx context.Context, gameType models.GameType, from, to time.Time) (int, error)
+	// BackfillDraw stores a single past draw and its prizes and returns how many results were new
+	BackfillDraw(ctx context.Context, gameType models.GameType, drawID uint) (int, error)
+	// UpdatePrizes fetches and stores the prize breakdowns of the results' draws
+	UpdatePrizes(ctx context.Context, results []models.Result) error
+}
+
+// GameObserver is told about every game whose info was fetched and saved
+type GameObserver interface {
+	GameUpdated(ctx context.Context, game models.Game)
 }
 
 type service struct {
 	lottoClient lotto.Client
 	repo        repository.Repository
+	// outboxChannels get an outbox message for every batch of new results
+	outboxChannels []string
+	observers      []GameObserver
 }
 
 func NewService(
 	lottoClient lotto.Client,
 	repo repository.Repository,
+	outboxChannels []string,
+	observers ...GameObserver,
 ) Service {
 	return &service{
-		lottoClient: lottoClient,
-		repo:        repo,
+		lottoClient:    lottoClient,
+		repo:           repo,
+		outboxChannels: outboxChannels,
+		observers:      observers,
 	}
 }
 
@@ -62,6 +85,7 @@ func (s *service) UpdateAllGames(ctx context.Context) ([]models.Game, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to update games: %w", err)
 	}
+	s.gamesUpdated(ctx, games)
 
 	return games, nil
 }
@@ -85,10 +109,19 @@ func (s *service) UpdateGame(ctx context.Context, gameType models.GameType) (mod
 	if err != nil {
 		return models.Game{}, fmt.Errorf("failed to update game: %w", err)
 	}
+	s.gamesUpdated(ctx, []models.Game{game})
 
 	return game, nil
 }
 
+func (s *service) gamesUpdated(ctx context.Context, games []models.Game) {
+	for _, observer := range s.observers {
+		for _, game := range games {
+			observer.GameUpdated(ctx, game)
+		}
+	}
+}
+
 func (s *service) GetAndSaveNewestResults(
 	ctx context.Context, gameType models.GameType, nextDrawDate time.Time,
 ) ([]models.Result, error) {
@@ -114,25 +147,116 @@ func (s *service) GetAndSaveNewestResults(
 		return nil, ErrMainGameNotFound
 	}
 
-	results := make([]models.Result, len(draws))
-	for idx, draw := range draws {
-		if len(draw.Results) == 0 {
-			return nil, ErrNoResultsInDraw
+	validDraws := make([]models.Draw, 0, len(draws))
+	var results []models.Result
+	for _, lottoDraw := range draws {
+		draw, err := drawFromLotto(lottoDraw)
+		if err != nil {
+			return nil, err
 		}
-		results[idx] = models.Result{
-			DrawID:         draw.DrawSystemID,
-			GameType:       models.GameType(draw.GameType),
-			DrawDate:       draw.DrawDate,
-			Results:        draw.Results[0].Results,
-			SpecialResults: draw.Results[0].SpecialResults,
-			CreatedAt:      time.Now(),
+
+		draw, err = s.validateDraw(ctx, draw)
+		if err != nil {
+			return nil, err
 		}
+		validDraws = append(validDraws, draw)
+		results = append(results, draw.Results...)
+	}
+	// nothing is saved so the draw is retried as a whole until the API serves a valid result
+	if !slices.ContainsFunc(results, func(result models.Result) bool { return result.GameType == gameType }) {
+		return nil, ErrMainResultInvalid
 	}
 
-	err = s.repo.InsertResults(ctx, results)
+	// only new results are returned and notified, their prizes are left to the prizes poller
+	inserted, err := s.repo.SaveDraws(ctx, gameType, validDraws, s.outboxChannels)
 	if err != nil {
-		return nil, fmt.Errorf("failed to insert results: %w", err)
+		return nil, fmt.Errorf("failed to save results: %w", err)
+	}
+
+	return inserted, nil
+}
+
+// drawFromLotto keeps every result set of the draw, each with its own game type and draw ID
+func drawFromLotto(lottoDraw lotto.Draw) (models.Draw, error) {
+	if len(lottoDraw.Results) == 0 {
+		return models.Draw{}, ErrNoResultsInDraw
 	}
 
-	return results, nil
+	now := time.Now()
+	draw := models.Draw{
+		DrawID:               lottoDraw.DrawSystemID,
+		GameType:             models.GameType(lottoDraw.GameType),
+		DrawDate:             lottoDraw.DrawDate,
+		MultiplierValue:      lottoDraw.MultiplierValue,
+		ShowSpecialResults:   lottoDraw.ShowSpecialResults,
+		IsNewEuroJackpotDraw: lottoDraw.IsNewEuroJackpotDraw,
+		CreatedAt:            now,
+		Results:              make([]models.Result, 0, len(lottoDraw.Results)),
+	}
+	for _, lottoResult := range lottoDraw.Results {
+		result := models.Result{
+			DrawID:         lottoResult.DrawSystemID,
+			GameType:       models.GameType(lottoResult.GameType),
+			DrawDate:       lottoResult.DrawDate,
+			Results:        lottoResult.Results,
+			SpecialResults: lottoResult.SpecialResults,
+			CreatedAt:      now,
+			ParentDrawID:   &draw.DrawID,
+			ParentGameType: &draw.GameType,
+		}
+		// the result sets usually repeat the draw's fields, fall back to them when they do not
+		if result.DrawID == 0 {
+			result.DrawID = draw.DrawID
+		}
+		if result.GameType == "" {
+			result.GameType = draw.GameType
+		}
+		if result.DrawDate.IsZero() {
+			result.DrawDate = draw.DrawDate
+		}
+		draw.Results = append(draw.Results, result)
+	}
+	return draw, nil
+}
+
+// validateDraw drops the invalid results of the draw, they are quarantined by validateResult
+func (s *service) validateDraw(ctx context.Context, draw models.Draw) (models.Draw, error) {
+	valid := make([]models.Result, 0, len(draw.Results))
+	for _, result := range draw.Results {
+		ok, err := s.validateResult(ctx, result)
+		if err != nil {
+			return models.Draw{}, err
+		}
+		if ok {
+			valid = append(valid, result)
+		}
+	}
+	draw.Results = valid
+	return draw, nil
+}
+
+// validateResult checks the result against the game rules and quarantines it when it is invalid,
+// so a malformed API response is never stored or notified
+func (s *service) validateResult(ctx context.Context, result models.Result) (bool, error) {
+	err := rules.ValidateResult(result)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, rules.ErrUnknownGame):
+		slog.Debug("No rules to validate result against", "game", result.GameType)
+		return true, nil
+	}
+
+	slog.Warn("Quarantining invalid result",
+		"game", result.GameType,
+		"drawID", result.DrawID,
+		"results", result.Results,
+		"specialResults", result.SpecialResults,
+		"reason", err,
+	)
+	quarantined := models.QuarantinedResult{Result: result, Reason: err.Error()}
+	if err := s.repo.QuarantineResult(ctx, quarantined); err != nil {
+		return false, fmt.Errorf("failed to quarantine result: %w", err)
+	}
+	return false, nil
 }
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/repository"
+	"lotto-notifications/internal/repository/repositorytest"
+	"lotto-notifications/pkg/lotto"
+)
+
+// fakeLottoClient serves canned draws in a single page and counts the prize requests,
+// the prizes are published for the draws in prizes only
+type fakeLottoClient struct {
+	lotto.Client
+
+	draws      []lotto.Draw
+	prizes     map[uint]map[string]lotto.Prize
+	prizeCalls int
+}
+
+func (c *fakeLottoClient) GetLastResults(ctx context.Context, gameType string) ([]lotto.Draw, error) {
+	return c.draws, nil
+}
+
+func (c *fakeLottoClient) GetDrawResults(ctx context.Context, query lotto.DrawsQuery) (*lotto.DrawsPage, error) {
+	return &lotto.DrawsPage{TotalRows: len(c.draws), Items: c.draws}, nil
+}
+
+func (c *fakeLottoClient) GetDrawPrizes(ctx context.Context, gameType string, drawID uint) ([]lotto.DrawPrizes, error) {
+	c.prizeCalls++
+	prizes, ok := c.prizes[drawID]
+	if !ok {
+		return nil, nil
+	}
+	return []lotto.DrawPrizes{{DrawSystemID: drawID, GameType: gameType, Prizes: prizes}}, nil
+}
+
+// fakeRepository records what the service saves and quarantines
+type fakeRepository struct {
+	repository.Repository
+
+	saved       []models.Draw
+	quarantined []models.QuarantinedResult
+}
+
+func (r *fakeRepository) SaveDraws(
+	ctx context.Context, gameType models.GameType, draws []models.Draw, channels []string,
+) ([]models.Result, error) {
+	r.saved = append(r.saved, draws...)
+	var results []models.Result
+	for _, draw := range draws {
+		results = append(results, draw.Results...)
+	}
+	return results, nil
+}
+
+func (r *fakeRepository) QuarantineResult(ctx context.Context, result models.QuarantinedResult) error {
+	r.quarantined = append(r.quarantined, result)
+	return nil
+}
+
+func seq(first, last int) []int {
+	nums := make([]int, 0, last-first+1)
+	for num := first; num <= last; num++ {
+		nums = append(nums, num)
+	}
+	return nums
+}
+
+var drawDate = time.Date(2026, time.October, 17, 20, 0, 0, 0, time.UTC)
+
+// lottoDraw returns a Lotto draw with the given LottoPlus and Lotto numbers
+func lottoDraw(lottoNumbers, plusNumbers []int) lotto.Draw {
+	return lotto.Draw{
+		DrawSystemID: 7000,
+		DrawDate:     drawDate,
+		GameType:     string(models.GameTypeLotto),
+		Results: []lotto.Result{
+			{DrawSystemID: 7000, DrawDate: drawDate, GameType: string(models.GameTypeLotto), Results: lottoNumbers},
+			{DrawSystemID: 7000, DrawDate: drawDate, GameType: string(models.GameTypeLottoPlus), Results: plusNumbers},
+		},
+	}
+}
+
+func gameTypes(results []models.Result) []models.GameType {
+	types := make([]models.GameType, 0, len(results))
+	for _, result := range results {
+		types = append(types, result.GameType)
+	}
+	return types
+}
+
+func TestGetAndSaveNewestResults(t *testing.T) {
+	tests := []struct {
+		name            string
+		draws           []lotto.Draw
+		nextDrawDate    time.Time
+		wantErr         error
+		wantSaved       []models.GameType
+		wantQuarantined []models.GameType
+	}{
+		{
+			name:         "every result set is saved",
+			draws:        []lotto.Draw{lottoDraw(seq(1, 6), seq(11, 16))},
+			nextDrawDate: drawDate,
+			wantSaved:    []models.GameType{models.GameTypeLotto, models.GameTypeLottoPlus},
+		},
+		{
+			name:            "invalid add-on result is quarantined",
+			draws:           []lotto.Draw{lottoDraw(seq(1, 6), seq(11, 15))},
+			nextDrawDate:    drawDate,
+			wantSaved:       []models.GameType{models.GameTypeLotto},
+			wantQuarantined: []models.GameType{models.GameTypeLottoPlus},
+		},
+		{
+			name:            "invalid main result is not treated as done",
+			draws:           []lotto.Draw{lottoDraw(seq(1, 5), seq(11, 16))},
+			nextDrawDate:    drawDate,
+			wantErr:         ErrMainResultInvalid,
+			wantQuarantined: []models.GameType{models.GameTypeLotto},
+		},
+		{
+			name:         "previous draw",
+			draws:        []lotto.Draw{lottoDraw(seq(1, 6), seq(11, 16))},
+			nextDrawDate: drawDate.Add(time.Hour),
+			wantErr:      ErrResultsNotYetAvailable,
+		},
+		{
+			name:         "main game missing",
+			draws:        []lotto.Draw{{GameType: string(models.GameTypeMiniLotto), DrawDate: drawDate}},
+			nextDrawDate: drawDate,
+			wantErr:      ErrMainGameNotFound,
+		},
+		{
+			name:         "no draws",
+			nextDrawDate: drawDate,
+			wantErr:      ErrNoResultsAvailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRepository{}
+			client := &fakeLottoClient{draws: tt.draws}
+			s := NewService(client, repo, nil)
+
+			results, err := s.GetAndSaveNewestResults(context.Background(), models.GameTypeLotto, tt.nextDrawDate)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetAndSaveNewestResults() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := gameTypes(results); !slices.Equal(got, tt.wantSaved) {
+				t.Errorf("returned results = %v, want %v", got, tt.wantSaved)
+			}
+			var saved []models.Result
+			for _, draw := range repo.saved {
+				saved = append(saved, draw.Results...)
+			}
+			if got := gameTypes(saved); !slices.Equal(got, tt.wantSaved) {
+				t.Errorf("saved results = %v, want %v", got, tt.wantSaved)
+			}
+			var quarantined []models.Result
+			for _, result := range repo.quarantined {
+				quarantined = append(quarantined, result.Result)
+			}
+			if got := gameTypes(quarantined); !slices.Equal(got, tt.wantQuarantined) {
+				t.Errorf("quarantined results = %v, want %v", got, tt.wantQuarantined)
+			}
+			// the prizes are left to the prizes poller
+			if client.prizeCalls != 0 {
+				t.Errorf("requested prizes %d times, want none", client.prizeCalls)
+			}
+		})
+	}
+}
+
+func TestBackfillResultsStoresPrizes(t *testing.T) {
+	ctx := context.Background()
+	repo := repositorytest.New(t)
+	client := &fakeLottoClient{
+		draws: []lotto.Draw{lottoDraw(seq(1, 6), seq(11, 16))},
+		prizes: map[uint]map[string]lotto.Prize{
+			7000: {"1": {Winners: 1, PrizeValue: 2_000_000}, "4": {Winners: 5000, PrizeValue: 24}},
+		},
+	}
+	s := NewService(client, repo, nil)
+
+	inserted, err := s.BackfillResults(ctx, models.GameTypeLotto, drawDate.Add(-time.Hour), drawDate.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("BackfillResults() error = %v", err)
+	}
+	if inserted != 2 {
+		t.Errorf("BackfillResults() = %d, want 2", inserted)
+	}
+	// the prizes are stored by the backfill, the prizes poller must not pick the old draws up
+	due, err := repo.GetDuePrizeChecks(ctx, time.Now().Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("GetDuePrizeChecks() error = %v", err)
+	}
+	if len(due) != 0 {
+		t.Errorf("GetDuePrizeChecks() = %v, want no checks for backfilled draws", due)
+	}
+	for _, gameType := range []models.GameType{models.GameTypeLotto, models.GameTypeLottoPlus} {
+		prizes, err := repo.GetPrizes(ctx, gameType, 7000)
+		if err != nil {
+			t.Fatalf("GetPrizes() error = %v", err)
+		}
+		if len(prizes) != 2 {
+			t.Errorf("%s has %d prizes, want 2", gameType, len(prizes))
+		}
+	}
+
+	// a second run over the same range does not request the stored prizes again
+	client.prizeCalls = 0
+	if _, err := s.BackfillResults(ctx, models.GameTypeLotto, drawDate.Add(-2*time.Hour), drawDate.Add(time.Hour)); err != nil {
+		t.Fatalf("BackfillResults() error = %v", err)
+	}
+	if client.prizeCalls != 0 {
+		t.Errorf("requested prizes %d times, want none", client.prizeCalls)
+	}
+}
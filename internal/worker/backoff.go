@@ -0,0 +1,39 @@
+package worker
+
+import (
+	"context"
+	"time"
+)
+
+// the backoff starts at initialBackoff and doubles up to maxBackoff, replaced in tests
+var (
+	initialBackoff = 5 * time.Minute
+	maxBackoff     = 30 * time.Minute
+)
+
+type backoff struct {
+	current time.Duration
+	max     time.Duration
+}
+
+func newBackoff() *backoff {
+	return &backoff{
+		current: initialBackoff,
+		max:     maxBackoff,
+	}
+}
+
+// wait sleeps for the current backoff duration and doubles it for the next call.
+// It returns false when the context was cancelled.
+func (b *backoff) wait(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(b.current):
+		b.current *= 2
+		if b.current > b.max {
+			b.current = b.max
+		}
+		return true
+	}
+}
@@ -0,0 +1,10 @@
+package worker
+
+import "time"
+
+// drawTolerance is how much earlier than scheduled a stored draw may be
+// and still count as that draw, the API dates are not always exact
+const drawTolerance = time.Hour
+
+// now is the worker's clock, replaced in tests
+var now = time.Now
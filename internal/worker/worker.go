@@ -2,12 +2,16 @@ package worker
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"log/slog"
 	"time"
 
 	"lotto-notifications/internal/models"
 	"lotto-notifications/internal/repository"
+	"lotto-notifications/internal/rules"
 	"lotto-notifications/internal/service"
+	"lotto-notifications/pkg/lotto"
 )
 
 type ResultsWorker interface {
@@ -39,81 +43,199 @@ func NewResultsWorker(
 	}, nil
 }
 
+// Run checks the game draw after draw until the context is cancelled
 func (w *resultsWorker) Run(ctx context.Context) {
-	slog.Debug(
-		"Running worker",
-		"game", w.game.GameType,
-		"nextDrawDate", w.game.NextDrawDate,
-	)
-	now := time.Now()
-	timeUntilNextDraw := w.game.NextDrawDate.Sub(now)
+	if !w.catchUp(ctx) {
+		return
+	}
 
-	if timeUntilNextDraw > 0 {
-		slog.Info("Waiting for next draw",
+	for {
+		slog.Debug(
+			"Running worker",
 			"game", w.game.GameType,
 			"nextDrawDate", w.game.NextDrawDate,
-			"timeUntilNextDraw", timeUntilNextDraw,
 		)
-		select {
-		case <-ctx.Done():
+		timeUntilNextDraw := w.game.NextDrawDate.Sub(now())
+
+		if timeUntilNextDraw > 0 {
+			slog.Info("Waiting for next draw",
+				"game", w.game.GameType,
+				"nextDrawDate", w.game.NextDrawDate,
+				"timeUntilNextDraw", timeUntilNextDraw,
+			)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(timeUntilNextDraw):
+			}
+		}
+
+		if !w.do(ctx) {
 			return
-		case <-time.After(timeUntilNextDraw):
-			w.do(ctx)
 		}
 	}
 }
 
-func (w *resultsWorker) do(ctx context.Context) {
-	backoffDuration := 5 * time.Minute
-	maxBackoff := 30 * time.Minute
+// catchUp fetches the results of a draw that took place while the worker was not running,
+// e.g. when the process started between a draw and the API publishing its results.
+// It gives up once the game's next draw is due, the regular loop takes over from there.
+// It returns false when the context was cancelled.
+func (w *resultsWorker) catchUp(ctx context.Context) bool {
+	gameRules, ok := rules.Get(w.game.GameType)
+	if !ok {
+		return true
+	}
+
+	expected := gameRules.Schedule.PreviousDraw(now())
+	// a draw that is still the game's next draw is handled by the regular loop
+	if expected.IsZero() || !expected.Before(*w.game.NextDrawDate) {
+		return true
+	}
 
-	waitWithBackoff := func() bool {
-		select {
-		case <-ctx.Done():
+	newest, err := w.repo.GetNewestResult(ctx, string(w.game.GameType))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		slog.Info("No results saved yet, fetching the latest draw", "game", w.game.GameType)
+	case err != nil:
+		slog.Error("Failed to get newest result, skipping catch-up", "game", w.game.GameType, "error", err)
+		return true
+	case !newest.DrawDate.Before(expected.Add(-drawTolerance)):
+		return true
+	default:
+		missed := gameRules.Schedule.DrawsBetween(newest.DrawDate.Add(drawTolerance), expected)
+		if missed > 1 {
+			// the API only exposes the latest draw, older ones are lost
+			slog.Warn("Missed multiple draws, only the latest one can be recovered",
+				"game", w.game.GameType,
+				"missed", missed,
+				"newestSavedDraw", newest.DrawDate,
+			)
+		}
+		slog.Info("Missed draw detected, catching up",
+			"game", w.game.GameType,
+			"expectedDrawDate", expected,
+			"newestSavedDraw", newest.DrawDate,
+		)
+	}
+
+	catchUpCtx, cancel := context.WithTimeout(ctx, w.game.NextDrawDate.Sub(now()))
+	defer cancel()
+
+	results, err := w.awaitResults(catchUpCtx, expected.Add(-drawTolerance), newBackoff())
+	if err != nil {
+		if ctx.Err() != nil || !errors.Is(err, context.DeadlineExceeded) {
 			return false
-		case <-time.After(backoffDuration):
-			backoffDuration *= 2
-			if backoffDuration > maxBackoff {
-				backoffDuration = maxBackoff
-			}
-			return true
 		}
+		slog.Warn("Gave up catching up, next draw is due", "game", w.game.GameType, "expectedDrawDate", expected)
+		return true
 	}
 
+	w.logSaved(results)
+	return true
+}
+
+// do waits for the results of the awaited draw, saves them and moves the worker
+// to the next draw. It returns false when the context was cancelled.
+func (w *resultsWorker) do(ctx context.Context) bool {
+	b := newBackoff()
+	drawDate := *w.game.NextDrawDate
+
+	var game models.Game
 	for {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-			game, err := w.service.UpdateGame(ctx, w.game.GameType)
-			if err != nil {
-				slog.Error("Failed to update game", "error", err)
-			}
-			if err != nil || game.NextDrawDate == w.game.NextDrawDate {
-				// we continue and wait for success and results to be available
-				if !waitWithBackoff() {
-					return
-				}
-				continue
+		var err error
+		game, err = w.service.UpdateGame(ctx, w.game.GameType)
+		if err != nil {
+			if w.isFatal(err) {
+				return false
 			}
+			logLottoError("Failed to update game", w.game.GameType, err)
+		}
+		if err == nil && game.NextDrawDate.After(drawDate) {
+			break
+		}
+		// we continue and wait for success and results to be available
+		if !b.wait(ctx) {
+			return false
+		}
+	}
 
-			_, err = w.service.GetAndSaveNewestResults(ctx, w.game.GameType, *game.NextDrawDate)
-			if err != nil {
-				if err != service.ErrResultsNotYetAvailable {
-					slog.Error("Failed to get and save newest results", "error", err)
-				}
-				// we continue and wait for success and results to be available
-				if !waitWithBackoff() {
-					return
-				}
-				continue
-			}
+	results, err := w.awaitResults(ctx, drawDate, b)
+	if err != nil {
+		return false
+	}
 
-			// we successfully updated the game and saved the results
-			// now the worker will wait for the next draw
-			slog.Info("Successfully updated game and saved results", "game", game.GameType)
-			w.game = game
-			return
+	// we successfully updated the game and saved the results
+	// now the worker will wait for the next draw
+	slog.Info("Successfully updated game and saved results", "game", game.GameType)
+	w.refreshGame(ctx, game)
+	w.logSaved(results)
+	return true
+}
+
+// awaitResults polls for the results of the draw held at drawDate until they are saved.
+// It fails when the context was cancelled or the worker cannot continue.
+func (w *resultsWorker) awaitResults(ctx context.Context, drawDate time.Time, b *backoff) ([]models.Result, error) {
+	for {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
 		}
+
+		results, err := w.service.GetAndSaveNewestResults(ctx, w.game.GameType, drawDate)
+		if err == nil {
+			return results, nil
+		}
+		if w.isFatal(err) {
+			return nil, err
+		}
+		if err != service.ErrResultsNotYetAvailable {
+			logLottoError("Failed to get and save newest results", w.game.GameType, err)
+		}
+		// we continue and wait for success and results to be available
+		if !b.wait(ctx) {
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// isFatal reports and logs errors retrying cannot fix, a rejected API key stays rejected
+func (w *resultsWorker) isFatal(err error) bool {
+	if !lotto.IsUnauthorized(err) {
+		return false
+	}
+	slog.Error("Lotto API rejected the API key, stopping worker, check LOTTO_API_KEY",
+		"game", w.game.GameType,
+		"error", err,
+	)
+	return true
+}
+
+// logLottoError logs a failed call, rate limiting is expected now and then and only warned about
+func logLottoError(msg string, gameType models.GameType, err error) {
+	if lotto.IsRateLimited(err) || errors.Is(err, lotto.ErrCircuitOpen) {
+		slog.Warn(msg, "game", gameType, "error", err)
+		return
+	}
+	slog.Error(msg, "game", gameType, "error", err)
+}
+
+// logSaved reports the saved results, their notifications were queued in the outbox
+// together with them and are delivered by the outbox relay
+func (w *resultsWorker) logSaved(results []models.Result) {
+	if len(results) == 0 {
+		slog.Info("Results were already saved, skipping notifications", "game", w.game.GameType)
+		return
+	}
+	slog.Info("Notifications queued", "game", w.game.GameType, "results", len(results))
+}
+
+// refreshGame re-reads the stored game so the worker continues with the saved state,
+// falling back to the game returned by the update if it cannot be read
+func (w *resultsWorker) refreshGame(ctx context.Context, updated models.Game) {
+	game, err := w.repo.GetGame(ctx, string(updated.GameType))
+	if err != nil || game.NextDrawDate == nil {
+		slog.Error("Failed to re-read game, using fetched game info", "game", updated.GameType, "error", err)
+		w.game = updated
+		return
 	}
+	w.game = game
 }
@@ -0,0 +1,352 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"lotto-notifications/internal/models"
+	"lotto-notifications/internal/repository"
+	"lotto-notifications/internal/rules"
+	"lotto-notifications/internal/service"
+	"lotto-notifications/pkg/lotto"
+)
+
+// fakeService plays the lotto API for a worker: every draw is announced by UpdateGame
+// one call late and its results are published one poll late
+type fakeService struct {
+	service.Service
+
+	mu        sync.Mutex
+	draws     []time.Time
+	current   int
+	updates   int
+	polls     int
+	polled    []time.Time
+	saved     int
+	updateErr error
+	// ready reports whether the results of the polled draw are published, every second poll when nil
+	ready   func(drawDate time.Time) bool
+	onSaved func(saved int)
+}
+
+func (s *fakeService) UpdateGame(ctx context.Context, gameType models.GameType) (models.Game, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.updateErr != nil {
+		return models.Game{}, s.updateErr
+	}
+	s.updates++
+	if s.updates%2 == 0 && s.current < len(s.draws)-1 {
+		s.current++
+	}
+	return s.game(gameType), nil
+}
+
+func (s *fakeService) GetAndSaveNewestResults(
+	ctx context.Context, gameType models.GameType, drawDate time.Time,
+) ([]models.Result, error) {
+	s.mu.Lock()
+	s.polls++
+	s.polled = append(s.polled, drawDate)
+	ready := s.polls%2 == 0
+	if s.ready != nil {
+		ready = s.ready(drawDate)
+	}
+	if !ready {
+		s.mu.Unlock()
+		return nil, service.ErrResultsNotYetAvailable
+	}
+	s.saved++
+	saved, onSaved := s.saved, s.onSaved
+	s.mu.Unlock()
+
+	if onSaved != nil {
+		onSaved(saved)
+	}
+	return []models.Result{{GameType: gameType, DrawDate: drawDate}}, nil
+}
+
+func (s *fakeService) game(gameType models.GameType) models.Game {
+	next := s.draws[s.current]
+	return models.Game{GameType: gameType, NextDrawDate: &next}
+}
+
+func (s *fakeService) polledDates() []time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.polled)
+}
+
+// fakeRepository stores what the worker reads back, the game comes from the fake service
+type fakeRepository struct {
+	repository.Repository
+
+	service   *fakeService
+	newest    *models.Result
+	newestErr error
+}
+
+func (r *fakeRepository) GetGame(ctx context.Context, gameType string) (models.Game, error) {
+	r.service.mu.Lock()
+	defer r.service.mu.Unlock()
+	return r.service.game(models.GameType(gameType)), nil
+}
+
+func (r *fakeRepository) GetNewestResult(ctx context.Context, gameType string) (models.Result, error) {
+	if r.newestErr != nil {
+		return models.Result{}, r.newestErr
+	}
+	if r.newest == nil {
+		return models.Result{}, sql.ErrNoRows
+	}
+	return *r.newest, nil
+}
+
+// lottoDraw returns the Lotto draw held on the given day of October 2026
+func lottoDraw(day int) time.Time {
+	return time.Date(2026, time.October, day, 22, 0, 0, 0, rules.Location())
+}
+
+func setClock(t *testing.T, at time.Time) {
+	t.Helper()
+	previous := now
+	now = func() time.Time { return at }
+	t.Cleanup(func() { now = previous })
+}
+
+func fastBackoff(t *testing.T) {
+	t.Helper()
+	previousInitial, previousMax := initialBackoff, maxBackoff
+	initialBackoff, maxBackoff = time.Millisecond, 2*time.Millisecond
+	t.Cleanup(func() { initialBackoff, maxBackoff = previousInitial, previousMax })
+}
+
+func newTestWorker(t *testing.T, svc *fakeService, repo *fakeRepository) *resultsWorker {
+	t.Helper()
+	repo.service = svc
+	w, err := NewResultsWorker(svc.game(models.GameTypeLotto), repo, svc)
+	if err != nil {
+		t.Fatalf("NewResultsWorker() error = %v", err)
+	}
+	return w.(*resultsWorker)
+}
+
+// runUntilDone runs the worker and fails the test if it does not stop in time
+func runUntilDone(t *testing.T, ctx context.Context, w ResultsWorker) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("worker did not stop")
+	}
+}
+
+func TestRunStepsThroughDraws(t *testing.T) {
+	fastBackoff(t)
+	// the clock stands after the third draw, so the first three are due right away
+	setClock(t, lottoDraw(17).Add(time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	svc := &fakeService{
+		draws: []time.Time{lottoDraw(13), lottoDraw(15), lottoDraw(17), lottoDraw(20)},
+		onSaved: func(saved int) {
+			if saved == 3 {
+				cancel()
+			}
+		},
+	}
+	repo := &fakeRepository{newest: &models.Result{DrawDate: lottoDraw(17)}}
+	w := newTestWorker(t, svc, repo)
+
+	runUntilDone(t, ctx, w)
+
+	// every draw is polled once before and once after its results are published
+	want := []time.Time{
+		lottoDraw(13), lottoDraw(13),
+		lottoDraw(15), lottoDraw(15),
+		lottoDraw(17), lottoDraw(17),
+	}
+	if got := svc.polledDates(); !slices.EqualFunc(got, want, time.Time.Equal) {
+		t.Errorf("polled draws = %v, want %v", got, want)
+	}
+	if svc.saved != 3 {
+		t.Errorf("saved draws = %d, want 3", svc.saved)
+	}
+	if got := *w.game.NextDrawDate; !got.Equal(lottoDraw(20)) {
+		t.Errorf("next draw = %v, want %v", got, lottoDraw(20))
+	}
+}
+
+func TestCatchUp(t *testing.T) {
+	tests := []struct {
+		name      string
+		clock     time.Time
+		nextDraw  time.Time
+		newest    *models.Result
+		newestErr error
+		want      []time.Time
+	}{
+		{
+			name:     "missed draw is fetched",
+			clock:    lottoDraw(17).Add(time.Hour),
+			nextDraw: lottoDraw(20),
+			newest:   &models.Result{DrawDate: lottoDraw(15)},
+			want:     []time.Time{lottoDraw(17).Add(-drawTolerance)},
+		},
+		{
+			name:     "several missed draws fetch the latest one",
+			clock:    lottoDraw(17).Add(time.Hour),
+			nextDraw: lottoDraw(20),
+			newest:   &models.Result{DrawDate: lottoDraw(10)},
+			want:     []time.Time{lottoDraw(17).Add(-drawTolerance)},
+		},
+		{
+			name:     "no results saved yet",
+			clock:    lottoDraw(17).Add(time.Hour),
+			nextDraw: lottoDraw(20),
+			want:     []time.Time{lottoDraw(17).Add(-drawTolerance)},
+		},
+		{
+			name:     "latest draw already saved",
+			clock:    lottoDraw(17).Add(time.Hour),
+			nextDraw: lottoDraw(20),
+			newest:   &models.Result{DrawDate: lottoDraw(17).Add(-time.Minute)},
+		},
+		{
+			name:     "latest draw is still the next draw",
+			clock:    lottoDraw(17).Add(time.Hour),
+			nextDraw: lottoDraw(17),
+			newest:   &models.Result{DrawDate: lottoDraw(15)},
+		},
+		{
+			name:      "newest result cannot be read",
+			clock:     lottoDraw(17).Add(time.Hour),
+			nextDraw:  lottoDraw(20),
+			newestErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fastBackoff(t)
+			setClock(t, tt.clock)
+
+			svc := &fakeService{
+				draws: []time.Time{tt.nextDraw},
+				ready: func(time.Time) bool { return true },
+			}
+			repo := &fakeRepository{newest: tt.newest, newestErr: tt.newestErr}
+			w := newTestWorker(t, svc, repo)
+
+			if !w.catchUp(context.Background()) {
+				t.Fatal("catchUp() = false, want true")
+			}
+			if got := svc.polledDates(); !slices.EqualFunc(got, tt.want, time.Time.Equal) {
+				t.Errorf("polled draws = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunCatchesUpBeforeWaiting(t *testing.T) {
+	fastBackoff(t)
+	setClock(t, lottoDraw(17).Add(time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	svc := &fakeService{
+		draws:   []time.Time{lottoDraw(20)},
+		onSaved: func(int) { cancel() },
+	}
+	repo := &fakeRepository{newest: &models.Result{DrawDate: lottoDraw(15)}}
+	w := newTestWorker(t, svc, repo)
+
+	runUntilDone(t, ctx, w)
+
+	// the missed draw is polled until its results are published, the next draw is not due yet
+	missed := lottoDraw(17).Add(-drawTolerance)
+	want := []time.Time{missed, missed}
+	if got := svc.polledDates(); !slices.EqualFunc(got, want, time.Time.Equal) {
+		t.Errorf("polled draws = %v, want %v", got, want)
+	}
+}
+
+func TestRunStopsWhenCancelled(t *testing.T) {
+	tests := []struct {
+		name  string
+		draws []time.Time
+		// cancelAfterPolls cancels the context once the worker polled that many times, 0 cancels right away
+		cancelAfterPolls int
+	}{
+		{
+			name:  "while waiting for the next draw",
+			draws: []time.Time{lottoDraw(20)},
+		},
+		{
+			name:             "while polling for results",
+			draws:            []time.Time{lottoDraw(17), lottoDraw(20)},
+			cancelAfterPolls: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fastBackoff(t)
+			setClock(t, lottoDraw(17).Add(time.Hour))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			svc := &fakeService{draws: tt.draws}
+			svc.ready = func(time.Time) bool {
+				if len(svc.polled) == tt.cancelAfterPolls {
+					cancel()
+				}
+				return false
+			}
+			repo := &fakeRepository{newest: &models.Result{DrawDate: lottoDraw(17)}}
+			w := newTestWorker(t, svc, repo)
+
+			if tt.cancelAfterPolls == 0 {
+				cancel()
+			}
+			runUntilDone(t, ctx, w)
+
+			if got := len(svc.polledDates()); got != tt.cancelAfterPolls {
+				t.Errorf("polls = %d, want %d", got, tt.cancelAfterPolls)
+			}
+		})
+	}
+}
+
+func TestRunStopsWhenUnauthorized(t *testing.T) {
+	fastBackoff(t)
+	setClock(t, lottoDraw(17).Add(time.Hour))
+
+	svc := &fakeService{
+		draws:     []time.Time{lottoDraw(17)},
+		updateErr: &lotto.APIError{StatusCode: http.StatusUnauthorized},
+	}
+	repo := &fakeRepository{newest: &models.Result{DrawDate: lottoDraw(17)}}
+	w := newTestWorker(t, svc, repo)
+
+	runUntilDone(t, context.Background(), w)
+
+	if got := len(svc.polledDates()); got != 0 {
+		t.Errorf("polls = %d, want 0", got)
+	}
+}
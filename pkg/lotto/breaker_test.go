@@ -0,0 +1,131 @@
+package lotto
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerStates(t *testing.T) {
+	const cooldown = 50 * time.Millisecond
+	api, server := newFakeAPI(t,
+		fakeResponse{status: http.StatusInternalServerError},
+		fakeResponse{status: http.StatusInternalServerError},
+		fakeResponse{status: http.StatusInternalServerError},
+		fakeResponse{status: http.StatusOK, body: `[]`},
+	)
+	breaker := NewCircuitBreaker(2, cooldown)
+	c := newTestClient(server, WithCircuitBreaker(breaker), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	ctx := context.Background()
+
+	request := func() error {
+		t.Helper()
+		_, err := c.GetLastResults(ctx, "Lotto")
+		return err
+	}
+
+	if got := breaker.State(); got != CircuitClosed {
+		t.Fatalf("initial state = %v, want closed", got)
+	}
+	request()
+	if got := breaker.State(); got != CircuitClosed {
+		t.Errorf("state after 1 failure = %v, want closed", got)
+	}
+	request()
+	if got := breaker.State(); got != CircuitOpen {
+		t.Fatalf("state after 2 failures = %v, want open", got)
+	}
+
+	// open, the API is not called
+	if err := request(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("request while open error = %v, want ErrCircuitOpen", err)
+	}
+	if got := api.requestCount(); got != 2 {
+		t.Errorf("made %d requests, want the open breaker to stop the third", got)
+	}
+
+	// the trial request after the cooldown fails and reopens the circuit
+	time.Sleep(cooldown)
+	if got := breaker.State(); got != CircuitHalfOpen {
+		t.Fatalf("state after the cooldown = %v, want half-open", got)
+	}
+	if err := request(); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("trial request error = %v, want the server error", err)
+	}
+	if got := breaker.State(); got != CircuitOpen {
+		t.Fatalf("state after a failed trial = %v, want open", got)
+	}
+
+	// the next trial succeeds and closes the circuit
+	time.Sleep(cooldown)
+	if err := request(); err != nil {
+		t.Fatalf("trial request error = %v", err)
+	}
+	if got := breaker.State(); got != CircuitClosed {
+		t.Errorf("state after a successful trial = %v, want closed", got)
+	}
+	if got := api.requestCount(); got != 4 {
+		t.Errorf("made %d requests, want 4", got)
+	}
+}
+
+func TestCircuitBreakerIgnoresClientErrors(t *testing.T) {
+	_, server := newFakeAPI(t, fakeResponse{status: http.StatusNotFound})
+	breaker := NewCircuitBreaker(2, time.Minute)
+	c := newTestClient(server, WithCircuitBreaker(breaker))
+
+	for range 5 {
+		if _, err := c.GetLastResults(context.Background(), "Keno"); !IsNotFound(err) {
+			t.Fatalf("GetLastResults() error = %v, want not found", err)
+		}
+	}
+	if got := breaker.State(); got != CircuitClosed {
+		t.Errorf("state = %v, want closed, a 404 shows the API is up", got)
+	}
+}
+
+func TestCircuitBreakerSingleTrial(t *testing.T) {
+	breaker := NewCircuitBreaker(1, time.Millisecond)
+	breaker.record(false)
+	time.Sleep(2 * time.Millisecond)
+
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("allow() for the trial error = %v", err)
+	}
+	if err := breaker.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("allow() during the trial error = %v, want ErrCircuitOpen", err)
+	}
+
+	// a cancelled trial lets the next request try
+	breaker.release()
+	if err := breaker.allow(); err != nil {
+		t.Errorf("allow() after a released trial error = %v", err)
+	}
+}
+
+func TestCircuitStateString(t *testing.T) {
+	for state, want := range map[CircuitState]string{
+		CircuitClosed:    "closed",
+		CircuitOpen:      "open",
+		CircuitHalfOpen:  "half-open",
+		CircuitState(42): "unknown",
+	} {
+		if got := state.String(); got != want {
+			t.Errorf("CircuitState(%d).String() = %q, want %q", state, got, want)
+		}
+	}
+}
+
+func TestNilCircuitBreaker(t *testing.T) {
+	var breaker *CircuitBreaker
+	if err := breaker.allow(); err != nil {
+		t.Errorf("allow() error = %v", err)
+	}
+	breaker.record(false)
+	breaker.release()
+	if got := breaker.State(); got != CircuitClosed {
+		t.Errorf("State() = %v, want closed", got)
+	}
+}
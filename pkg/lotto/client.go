@@ -6,32 +6,126 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 )
 
 const (
-	baseURL = "https://developers.lotto.pl/api/open/v1"
+	DefaultBaseURL   = "https://developers.lotto.pl/api/open/v1"
+	DefaultTimeout   = 10 * time.Second
+	DefaultUserAgent = "lotto-notifications"
 )
 
 type Client interface {
 	GetLastResults(ctx context.Context, gameType string) ([]Draw, error)
 	GetGameInfo(ctx context.Context, gameType string) (*GameInfo, error)
+	// GetDrawResults returns a page of the game's draws held between query.From and query.To, oldest first
+	GetDrawResults(ctx context.Context, query DrawsQuery) (*DrawsPage, error)
+	// GetDrawResultsByID returns the draw with the given draw system ID
+	GetDrawResultsByID(ctx context.Context, gameType string, drawID uint) ([]Draw, error)
+	// GetDrawPrizes returns the prize breakdowns of a draw, they are published some time after the results
+	GetDrawPrizes(ctx context.Context, gameType string, drawID uint) ([]DrawPrizes, error)
+}
+
+// DrawsQuery selects a page of historical draws, pages are numbered from 1
+type DrawsQuery struct {
+	GameType string
+	From     time.Time
+	To       time.Time
+	Page     int
+	Size     int
 }
 
 type client struct {
 	httpClient *http.Client
 	apiKey     string
+	baseURL    string
+	userAgent  string
+	timeout    time.Duration
+	retry      RetryPolicy
+	limiter    *Limiter
+	breaker    *CircuitBreaker
+}
+
+// Option configures the client created by NewClient
+type Option func(*client)
+
+// WithBaseURL points the client at another API, e.g. a local mock
+func WithBaseURL(baseURL string) Option {
+	return func(c *client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithHTTPClient makes the client send its requests with httpClient, e.g. to use a proxy or an instrumented transport
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTimeout limits how long a single request may take, it does not modify a client passed to WithHTTPClient
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *client) {
+		c.timeout = timeout
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *client) {
+		c.retry = policy
+	}
 }
 
-func NewClient(apiKey string) Client {
-	return &client{
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-		apiKey: apiKey,
+// WithLimiter limits the requests with limiter, pass the same limiter to clients that share the API quota.
+// A nil limiter disables rate limiting.
+func WithLimiter(limiter *Limiter) Option {
+	return func(c *client) {
+		c.limiter = limiter
 	}
 }
 
+// WithCircuitBreaker guards the requests with breaker, keep the breaker to report its state.
+// A nil breaker disables it.
+func WithCircuitBreaker(breaker *CircuitBreaker) Option {
+	return func(c *client) {
+		c.breaker = breaker
+	}
+}
+
+func NewClient(apiKey string, opts ...Option) Client {
+	c := &client{
+		httpClient: &http.Client{},
+		apiKey:     apiKey,
+		baseURL:    DefaultBaseURL,
+		userAgent:  DefaultUserAgent,
+		timeout:    DefaultTimeout,
+		retry:      DefaultRetryPolicy,
+		limiter:    NewLimiter(1, 2),
+		breaker:    NewCircuitBreaker(5, time.Minute),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.timeout > 0 && c.httpClient.Timeout != c.timeout {
+		httpClient := *c.httpClient
+		httpClient.Timeout = c.timeout
+		c.httpClient = &httpClient
+	}
+	return c
+}
+
 func (c *client) prepareRequest(ctx context.Context, method, url string) (*http.Request, error) {
 	req, err := http.NewRequestWithContext(ctx, method, url, nil)
 	if err != nil {
@@ -40,60 +134,145 @@ func (c *client) prepareRequest(ctx context.Context, method, url string) (*http.
 
 	req.Header.Set("secret", c.apiKey)
 	req.Header.Set("Accept", "application/json")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
 
 	return req, nil
 }
 
 func (c *client) GetLastResults(ctx context.Context, gameType string) ([]Draw, error) {
-	url := fmt.Sprintf("%s/lotteries/draw-results/last-results-per-game?gameType=%s", baseURL, gameType)
+	url := fmt.Sprintf("%s/lotteries/draw-results/last-results-per-game?gameType=%s", c.baseURL, gameType)
 
-	req, err := c.prepareRequest(ctx, "GET", url)
-	if err != nil {
+	var results []Draw
+	if err := c.get(ctx, url, &results); err != nil {
 		return nil, err
 	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+	return results, nil
+}
+
+func (c *client) GetGameInfo(ctx context.Context, gameType string) (*GameInfo, error) {
+	url := fmt.Sprintf("%s/lotteries/info?gameType=%s", c.baseURL, gameType)
+
+	var info GameInfo
+	if err := c.get(ctx, url, &info); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		slog.Error("unexpected status code", "status", resp.StatusCode, "body", resp.Body)
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	return &info, nil
+}
+
+func (c *client) GetDrawResults(ctx context.Context, query DrawsQuery) (*DrawsPage, error) {
+	params := url.Values{}
+	params.Set("gameType", query.GameType)
+	params.Set("drawDateFrom", query.From.Format(time.DateOnly))
+	params.Set("drawDateTo", query.To.Format(time.DateOnly))
+	params.Set("index", strconv.Itoa(query.Page))
+	params.Set("size", strconv.Itoa(query.Size))
+	params.Set("sort", "drawDate")
+	params.Set("order", "ASC")
+	requestURL := fmt.Sprintf("%s/lotteries/draw-results/by-gametype?%s", c.baseURL, params.Encode())
+
+	var page DrawsPage
+	if err := c.get(ctx, requestURL, &page); err != nil {
+		return nil, err
 	}
 
-	var results []Draw
-	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	return &page, nil
+}
+
+func (c *client) GetDrawResultsByID(ctx context.Context, gameType string, drawID uint) ([]Draw, error) {
+	url := fmt.Sprintf("%s/lotteries/draw-results/by-number-per-game?gameType=%s&drawSystemId=%d", c.baseURL, gameType, drawID)
+
+	var draws []Draw
+	if err := c.get(ctx, url, &draws); err != nil {
+		return nil, err
 	}
 
-	return results, nil
+	return draws, nil
 }
 
-func (c *client) GetGameInfo(ctx context.Context, gameType string) (*GameInfo, error) {
-	url := fmt.Sprintf("%s/lotteries/info?gameType=%s", baseURL, gameType)
+func (c *client) GetDrawPrizes(ctx context.Context, gameType string, drawID uint) ([]DrawPrizes, error) {
+	url := fmt.Sprintf("%s/lotteries/draw-prizes/%s/%d", c.baseURL, gameType, drawID)
+
+	var prizes []DrawPrizes
+	if err := c.get(ctx, url, &prizes); err != nil {
+		return nil, err
+	}
+
+	return prizes, nil
+}
+
+// get requests url and decodes the response into out, retrying according to the retry policy
+func (c *client) get(ctx context.Context, url string, out any) error {
+	for attempt := 1; ; attempt++ {
+		wait, retryable, err := c.attempt(ctx, url, out)
+		if err == nil {
+			return nil
+		}
+		if !retryable || attempt >= c.retry.MaxAttempts || ctx.Err() != nil {
+			return err
+		}
+
+		if wait == 0 {
+			wait = c.retry.delay(attempt)
+		} else if wait > c.retry.MaxDelay {
+			// the API asked for a longer break than we are willing to block for
+			return err
+		}
+		slog.Debug("Retrying lotto API request", "url", url, "attempt", attempt, "wait", wait, "error", err)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt makes a single request. It reports whether the request may be retried
+// and how long the API asked us to wait before doing so, zero if it did not say.
+func (c *client) attempt(ctx context.Context, url string, out any) (time.Duration, bool, error) {
+	if err := c.breaker.allow(); err != nil {
+		return 0, false, err
+	}
+	if err := c.limiter.Wait(ctx); err != nil {
+		c.breaker.release()
+		return 0, false, err
+	}
 
 	req, err := c.prepareRequest(ctx, "GET", url)
 	if err != nil {
-		return nil, err
+		c.breaker.release()
+		return 0, false, err
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		if ctx.Err() != nil {
+			c.breaker.release()
+			return 0, false, fmt.Errorf("failed to make request: %w", err)
+		}
+		c.breaker.record(false)
+		return 0, true, fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	// any response but a server error shows the API is up
+	c.breaker.record(resp.StatusCode < http.StatusInternalServerError)
+
 	if resp.StatusCode != http.StatusOK {
-		slog.Error("unexpected status code", "status", resp.StatusCode, "body", resp.Body)
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		apiErr := newAPIError(resp)
+		slog.Debug("Lotto API error response", "status", apiErr.StatusCode, "url", apiErr.URL, "body", apiErr.Body)
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+		return retryAfter(resp), retryable, apiErr
 	}
 
-	var info GameInfo
-	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return 0, false, fmt.Errorf("failed to decode response: %w", err)
 	}
-
-	return &info, nil
+	return 0, false, nil
 }
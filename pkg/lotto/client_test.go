@@ -0,0 +1,250 @@
+package lotto
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// pagedAPI serves draws 1..total of the by-gametype endpoint, pages are numbered from 1
+type pagedAPI struct {
+	t     *testing.T
+	total int
+
+	mu    sync.Mutex
+	pages []string
+}
+
+func (a *pagedAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	index, err := strconv.Atoi(query.Get("index"))
+	if err != nil || index < 1 {
+		a.t.Errorf("index = %q, want a page number", query.Get("index"))
+	}
+	size, err := strconv.Atoi(query.Get("size"))
+	if err != nil || size < 1 {
+		a.t.Errorf("size = %q, want a page size", query.Get("size"))
+	}
+	a.mu.Lock()
+	a.pages = append(a.pages, query.Get("index"))
+	a.mu.Unlock()
+
+	page := DrawsPage{TotalRows: a.total, Items: []Draw{}}
+	for id := (index-1)*size + 1; id <= min(index*size, a.total); id++ {
+		page.Items = append(page.Items, Draw{DrawSystemID: uint(id), GameType: query.Get("gameType")})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+func TestGetDrawResultsRequest(t *testing.T) {
+	api, server := newFakeAPI(t, fakeResponse{status: http.StatusOK, body: `{"totalRows": 0, "items": []}`})
+
+	// the dates are sent as the day they fall on, whatever the time
+	_, err := newTestClient(server).GetDrawResults(context.Background(), DrawsQuery{
+		GameType: "Lotto",
+		From:     time.Date(2026, time.January, 2, 23, 30, 0, 0, time.UTC),
+		To:       time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC),
+		Page:     3,
+		Size:     50,
+	})
+	if err != nil {
+		t.Fatalf("GetDrawResults() error = %v", err)
+	}
+
+	req := api.requests[0]
+	if req.Method != http.MethodGet {
+		t.Errorf("method = %s, want GET", req.Method)
+	}
+	if want := "/lotteries/draw-results/by-gametype"; req.URL.Path != want {
+		t.Errorf("path = %q, want %q", req.URL.Path, want)
+	}
+	wantQuery := map[string]string{
+		"gameType":     "Lotto",
+		"drawDateFrom": "2026-01-02",
+		"drawDateTo":   "2026-10-18",
+		"index":        "3",
+		"size":         "50",
+		"sort":         "drawDate",
+		"order":        "ASC",
+	}
+	query := req.URL.Query()
+	if len(query) != len(wantQuery) {
+		t.Errorf("query = %q, want %d parameters", req.URL.RawQuery, len(wantQuery))
+	}
+	for key, want := range wantQuery {
+		if got := query.Get(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+	if got := req.Header.Get("secret"); got != "secret-key" {
+		t.Errorf("secret header = %q, want the API key", got)
+	}
+	if got := req.Header.Get("Accept"); got != "application/json" {
+		t.Errorf("Accept header = %q, want application/json", got)
+	}
+}
+
+func TestGetDrawResultsEscapesQuery(t *testing.T) {
+	api, server := newFakeAPI(t, fakeResponse{status: http.StatusOK, body: `{"totalRows": 0, "items": []}`})
+
+	_, err := newTestClient(server).GetDrawResults(context.Background(), DrawsQuery{GameType: "Lotto&size=1000", Page: 1, Size: 10})
+	if err != nil {
+		t.Fatalf("GetDrawResults() error = %v", err)
+	}
+	query := api.requests[0].URL.Query()
+	if got := query.Get("gameType"); got != "Lotto&size=1000" {
+		t.Errorf("gameType = %q, want it escaped", got)
+	}
+	if got := query["size"]; !slices.Equal(got, []string{"10"}) {
+		t.Errorf("size = %q, want [10]", got)
+	}
+}
+
+func TestGetDrawResultsDecodesPage(t *testing.T) {
+	body := `{
+		"totalRows": 120,
+		"items": [{
+			"drawSystemId": 7001,
+			"drawDate": "2026-10-17T20:00:00Z",
+			"gameType": "Lotto",
+			"multiplierValue": 0,
+			"results": [
+				{"drawSystemId": 7001, "drawDate": "2026-10-17T20:00:00Z", "gameType": "Lotto", "resultsJson": [1, 2, 3, 4, 5, 6], "specialResults": []},
+				{"drawSystemId": 7001, "drawDate": "2026-10-17T20:00:00Z", "gameType": "LottoPlus", "resultsJson": [7, 8, 9, 10, 11, 12], "specialResults": []}
+			],
+			"showSpecialResults": false,
+			"isNewEuroJackpotDraw": false
+		}]
+	}`
+	_, server := newFakeAPI(t, fakeResponse{status: http.StatusOK, body: body})
+
+	page, err := newTestClient(server).GetDrawResults(context.Background(), DrawsQuery{GameType: "Lotto", Page: 1, Size: 50})
+	if err != nil {
+		t.Fatalf("GetDrawResults() error = %v", err)
+	}
+	if page.TotalRows != 120 {
+		t.Errorf("TotalRows = %d, want 120", page.TotalRows)
+	}
+	if len(page.Items) != 1 {
+		t.Fatalf("got %d items, want 1", len(page.Items))
+	}
+	draw := page.Items[0]
+	if draw.DrawSystemID != 7001 || draw.GameType != "Lotto" {
+		t.Errorf("draw = %d %s, want 7001 Lotto", draw.DrawSystemID, draw.GameType)
+	}
+	if want := time.Date(2026, time.October, 17, 20, 0, 0, 0, time.UTC); !draw.DrawDate.Equal(want) {
+		t.Errorf("DrawDate = %v, want %v", draw.DrawDate, want)
+	}
+	if len(draw.Results) != 2 {
+		t.Fatalf("got %d results, want 2", len(draw.Results))
+	}
+	if got := draw.Results[1]; got.GameType != "LottoPlus" || !slices.Equal(got.Results, []int{7, 8, 9, 10, 11, 12}) {
+		t.Errorf("second result = %s %v, want LottoPlus [7 8 9 10 11 12]", got.GameType, got.Results)
+	}
+}
+
+func TestGetDrawResultsWalksPages(t *testing.T) {
+	tests := []struct {
+		name      string
+		total     int
+		size      int
+		wantPages []string
+	}{
+		{name: "short last page", total: 12, size: 5, wantPages: []string{"1", "2", "3"}},
+		{name: "full last page", total: 10, size: 5, wantPages: []string{"1", "2"}},
+		{name: "single page", total: 3, size: 5, wantPages: []string{"1"}},
+		{name: "no draws", total: 0, size: 5, wantPages: []string{"1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &pagedAPI{t: t, total: tt.total}
+			server := httptest.NewServer(api)
+			t.Cleanup(server.Close)
+			client := newTestClient(server)
+
+			// walked the way the backfill does, until a short page or the total is reached
+			var got []uint
+			for index := 1; index <= 10; index++ {
+				page, err := client.GetDrawResults(context.Background(), DrawsQuery{GameType: "Lotto", Page: index, Size: tt.size})
+				if err != nil {
+					t.Fatalf("GetDrawResults() page %d error = %v", index, err)
+				}
+				if page.TotalRows != tt.total {
+					t.Errorf("page %d TotalRows = %d, want %d", index, page.TotalRows, tt.total)
+				}
+				for _, draw := range page.Items {
+					got = append(got, draw.DrawSystemID)
+				}
+				if len(page.Items) < tt.size || index*tt.size >= page.TotalRows {
+					break
+				}
+			}
+
+			want := make([]uint, tt.total)
+			for idx := range want {
+				want[idx] = uint(idx + 1)
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("walked draws %v, want %v", got, want)
+			}
+			if !slices.Equal(api.pages, tt.wantPages) {
+				t.Errorf("requested pages %v, want %v", api.pages, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestGetDrawResultsByID(t *testing.T) {
+	body := `[{
+		"drawSystemId": 6789,
+		"drawDate": "2026-03-14T20:00:00Z",
+		"gameType": "Lotto",
+		"results": [{"drawSystemId": 6789, "gameType": "Lotto", "resultsJson": [4, 8, 15, 16, 23, 42]}]
+	}]`
+	api, server := newFakeAPI(t, fakeResponse{status: http.StatusOK, body: body})
+
+	draws, err := newTestClient(server).GetDrawResultsByID(context.Background(), "Lotto", 6789)
+	if err != nil {
+		t.Fatalf("GetDrawResultsByID() error = %v", err)
+	}
+
+	req := api.requests[0]
+	if want := "/lotteries/draw-results/by-number-per-game"; req.URL.Path != want {
+		t.Errorf("path = %q, want %q", req.URL.Path, want)
+	}
+	query := req.URL.Query()
+	if got := query.Get("gameType"); got != "Lotto" {
+		t.Errorf("gameType = %q, want Lotto", got)
+	}
+	if got := query.Get("drawSystemId"); got != "6789" {
+		t.Errorf("drawSystemId = %q, want 6789", got)
+	}
+
+	if len(draws) != 1 || draws[0].DrawSystemID != 6789 {
+		t.Fatalf("GetDrawResultsByID() = %v, want draw 6789", draws)
+	}
+	if got := draws[0].Results; len(got) != 1 || !slices.Equal(got[0].Results, []int{4, 8, 15, 16, 23, 42}) {
+		t.Errorf("results = %v, want [4 8 15 16 23 42]", got)
+	}
+}
+
+func TestGetDrawResultsByIDNotFound(t *testing.T) {
+	_, server := newFakeAPI(t, fakeResponse{status: http.StatusNotFound, body: `{"message": "Draw not found"}`})
+
+	_, err := newTestClient(server).GetDrawResultsByID(context.Background(), "Lotto", 1)
+	if !IsNotFound(err) {
+		t.Errorf("GetDrawResultsByID() error = %v, want not found", err)
+	}
+	if err != nil && !strings.Contains(err.Error(), "Draw not found") {
+		t.Errorf("error = %q, want the API message", err)
+	}
+}
@@ -0,0 +1,87 @@
+package lotto
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBody is how much of an error response is kept
+const maxErrorBody = 1024
+
+// APIError is returned when the API answers with anything but 200 OK
+type APIError struct {
+	StatusCode int
+	URL        string
+	// Body is the start of the response body
+	Body string
+	// Message is the error message found in a JSON body, empty if there was none
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("lotto API returned %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("lotto API returned %d", e.StatusCode)
+}
+
+// IsUnauthorized reports whether the API rejected the API key
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized, http.StatusForbidden)
+}
+
+// IsRateLimited reports whether the API asked us to slow down
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+// IsNotFound reports whether the API does not know the requested resource, e.g. an unknown game type
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+func hasStatus(err error, statusCodes ...int) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, statusCode := range statusCodes {
+		if apiErr.StatusCode == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+func newAPIError(resp *http.Response) *APIError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		URL:        resp.Request.URL.String(),
+		Body:       strings.TrimSpace(string(body)),
+		Message:    errorMessage(body),
+	}
+}
+
+// errorMessage finds the message in the usual shapes of JSON error bodies
+func errorMessage(body []byte) string {
+	var parsed struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+		Detail  string `json:"detail"`
+		Title   string `json:"title"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return ""
+	}
+	for _, message := range []string{parsed.Message, parsed.Detail, parsed.Error, parsed.Title} {
+		if message != "" {
+			return message
+		}
+	}
+	return ""
+}
@@ -0,0 +1,165 @@
+package lotto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAPIErrorMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantMessage string
+		wantError   string
+	}{
+		{
+			name:        "message",
+			body:        `{"message": "Invalid game type"}`,
+			wantMessage: "Invalid game type",
+			wantError:   "lotto API returned 400: Invalid game type",
+		},
+		{
+			name:        "problem details",
+			body:        `{"title": "Bad Request", "detail": "drawDateFrom is required"}`,
+			wantMessage: "drawDateFrom is required",
+			wantError:   "lotto API returned 400: drawDateFrom is required",
+		},
+		{
+			name:        "error",
+			body:        `{"error": "invalid_request"}`,
+			wantMessage: "invalid_request",
+			wantError:   "lotto API returned 400: invalid_request",
+		},
+		{
+			name:        "title only",
+			body:        `{"title": "Bad Request"}`,
+			wantMessage: "Bad Request",
+			wantError:   "lotto API returned 400: Bad Request",
+		},
+		{
+			name:      "plain text",
+			body:      "Bad Request\n",
+			wantError: "lotto API returned 400",
+		},
+		{
+			name:      "empty body",
+			wantError: "lotto API returned 400",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, server := newFakeAPI(t, fakeResponse{status: http.StatusBadRequest, body: tt.body})
+
+			_, err := newTestClient(server).GetGameInfo(context.Background(), "Lotto")
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("GetGameInfo() error = %v, want an APIError", err)
+			}
+			if apiErr.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", apiErr.Message, tt.wantMessage)
+			}
+			if apiErr.Body != strings.TrimSpace(tt.body) {
+				t.Errorf("Body = %q, want %q", apiErr.Body, strings.TrimSpace(tt.body))
+			}
+			if apiErr.Error() != tt.wantError {
+				t.Errorf("Error() = %q, want %q", apiErr.Error(), tt.wantError)
+			}
+			if want := server.URL + "/lotteries/info?gameType=Lotto"; apiErr.URL != want {
+				t.Errorf("URL = %q, want %q", apiErr.URL, want)
+			}
+		})
+	}
+}
+
+func TestAPIErrorTruncatesBody(t *testing.T) {
+	body := `{"message": "` + strings.Repeat("x", 3*maxErrorBody) + `"}`
+	_, server := newFakeAPI(t, fakeResponse{status: http.StatusBadRequest, body: body})
+
+	_, err := newTestClient(server).GetGameInfo(context.Background(), "Lotto")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("GetGameInfo() error = %v, want an APIError", err)
+	}
+	if len(apiErr.Body) != maxErrorBody || apiErr.Body != body[:maxErrorBody] {
+		t.Errorf("Body has %d bytes, want the first %d", len(apiErr.Body), maxErrorBody)
+	}
+	// the cut JSON cannot be parsed
+	if apiErr.Message != "" {
+		t.Errorf("Message = %q, want none", apiErr.Message)
+	}
+}
+
+func TestIsUnauthorized(t *testing.T) {
+	for _, status := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			api, server := newFakeAPI(t, fakeResponse{status: status, body: `{"message": "Invalid API key"}`})
+
+			_, err := newTestClient(server).GetGameInfo(context.Background(), "Lotto")
+			if !IsUnauthorized(err) {
+				t.Fatalf("IsUnauthorized(%v) = false, want true", err)
+			}
+			if IsRateLimited(err) || IsNotFound(err) {
+				t.Errorf("%v is reported as rate limited or not found", err)
+			}
+			if got := api.requests[0].Header.Get("secret"); got != "secret-key" {
+				t.Errorf("secret header = %q, want the API key", got)
+			}
+		})
+	}
+}
+
+func TestErrorChecks(t *testing.T) {
+	wrapped := func(status int) error {
+		return fmt.Errorf("failed to get game info: %w", &APIError{StatusCode: status})
+	}
+
+	tests := []struct {
+		name             string
+		err              error
+		wantUnauthorized bool
+		wantRateLimited  bool
+		wantNotFound     bool
+	}{
+		{name: "unauthorized", err: wrapped(http.StatusUnauthorized), wantUnauthorized: true},
+		{name: "forbidden", err: wrapped(http.StatusForbidden), wantUnauthorized: true},
+		{name: "rate limited", err: wrapped(http.StatusTooManyRequests), wantRateLimited: true},
+		{name: "not found", err: wrapped(http.StatusNotFound), wantNotFound: true},
+		{name: "doubly wrapped", err: fmt.Errorf("worker: %w", wrapped(http.StatusNotFound)), wantNotFound: true},
+		{name: "joined", err: errors.Join(errors.New("other"), wrapped(http.StatusUnauthorized)), wantUnauthorized: true},
+		{name: "server error", err: wrapped(http.StatusInternalServerError)},
+		{name: "not an API error", err: errors.New("connection refused")},
+		{name: "circuit open", err: ErrCircuitOpen},
+		{name: "nil"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsUnauthorized(tt.err); got != tt.wantUnauthorized {
+				t.Errorf("IsUnauthorized() = %t, want %t", got, tt.wantUnauthorized)
+			}
+			if got := IsRateLimited(tt.err); got != tt.wantRateLimited {
+				t.Errorf("IsRateLimited() = %t, want %t", got, tt.wantRateLimited)
+			}
+			if got := IsNotFound(tt.err); got != tt.wantNotFound {
+				t.Errorf("IsNotFound() = %t, want %t", got, tt.wantNotFound)
+			}
+		})
+	}
+}
+
+func TestIsRateLimitedAfterRetries(t *testing.T) {
+	api, server := newFakeAPI(t, fakeResponse{status: http.StatusTooManyRequests})
+
+	_, err := newTestClient(server).GetLastResults(context.Background(), "Lotto")
+	if !IsRateLimited(err) {
+		t.Fatalf("IsRateLimited(%v) = false, want true", err)
+	}
+	if got := api.requestCount(); got != fastRetries.MaxAttempts {
+		t.Errorf("made %d requests, want %d", got, fastRetries.MaxAttempts)
+	}
+}
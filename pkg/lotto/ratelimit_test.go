@@ -0,0 +1,92 @@
+package lotto
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLimiterSpacesConcurrentCallers(t *testing.T) {
+	const (
+		rate    = 20
+		callers = 6
+		spacing = time.Second / rate
+	)
+	limiter := NewLimiter(rate, 1)
+
+	var mu sync.Mutex
+	var times []time.Time
+	var wg sync.WaitGroup
+	start := time.Now()
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := limiter.Wait(context.Background()); err != nil {
+				t.Errorf("Wait() error = %v", err)
+			}
+			mu.Lock()
+			times = append(times, time.Now())
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	slices.SortFunc(times, func(a, b time.Time) int { return a.Compare(b) })
+	// the first caller takes the burst token, every other one waits for its own slot
+	if elapsed := times[len(times)-1].Sub(start); elapsed < (callers-1)*spacing-10*time.Millisecond {
+		t.Errorf("%d callers passed within %v, want at least %v", callers, elapsed, (callers-1)*spacing)
+	}
+	for idx := 1; idx < len(times); idx++ {
+		if gap := times[idx].Sub(times[idx-1]); gap < spacing/2 {
+			t.Errorf("callers %d and %d passed %v apart, want about %v", idx-1, idx, gap, spacing)
+		}
+	}
+}
+
+func TestLimiterBurst(t *testing.T) {
+	limiter := NewLimiter(1, 3)
+
+	start := time.Now()
+	for range 3 {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("burst of 3 took %v, want no wait", elapsed)
+	}
+}
+
+func TestLimiterWaitCancelled(t *testing.T) {
+	limiter := NewLimiter(1, 1)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); err == nil {
+		t.Fatal("Wait() succeeded, want the context error")
+	}
+
+	// the cancelled caller gave its slot back, the next one waits a single interval and not two
+	start := time.Now()
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 1100*time.Millisecond {
+		t.Errorf("waited %v after a cancelled caller, want at most 1s", elapsed)
+	}
+}
+
+func TestNilLimiterDoesNotWait(t *testing.T) {
+	var limiter *Limiter
+	for range 100 {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+}
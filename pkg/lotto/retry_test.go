@@ -0,0 +1,262 @@
+package lotto
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fastRetries retries quickly so the tests do not wait for the default backoff
+var fastRetries = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   time.Millisecond,
+	MaxDelay:    2 * time.Second,
+}
+
+// fakeAPI answers the requests with the given responses in turn, the last one is repeated.
+// A zero status closes the connection without answering.
+type fakeAPI struct {
+	t         *testing.T
+	responses []fakeResponse
+
+	mu       sync.Mutex
+	requests []*http.Request
+	times    []time.Time
+}
+
+type fakeResponse struct {
+	status     int
+	body       string
+	retryAfter string
+}
+
+func newFakeAPI(t *testing.T, responses ...fakeResponse) (*fakeAPI, *httptest.Server) {
+	t.Helper()
+	api := &fakeAPI{t: t, responses: responses}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	return api, server
+}
+
+func (a *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	idx := min(len(a.requests), len(a.responses)-1)
+	a.requests = append(a.requests, r)
+	a.times = append(a.times, time.Now())
+	resp := a.responses[idx]
+	a.mu.Unlock()
+
+	if resp.status == 0 {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			a.t.Errorf("failed to hijack connection: %v", err)
+			return
+		}
+		conn.Close()
+		return
+	}
+	if resp.retryAfter != "" {
+		w.Header().Set("Retry-After", resp.retryAfter)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(resp.status)
+	w.Write([]byte(resp.body))
+}
+
+func (a *fakeAPI) requestCount() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.requests)
+}
+
+// newTestClient returns a client of the fake API without rate limiting or a circuit breaker
+func newTestClient(server *httptest.Server, opts ...Option) Client {
+	opts = append([]Option{
+		WithBaseURL(server.URL),
+		WithRetryPolicy(fastRetries),
+		WithLimiter(nil),
+		WithCircuitBreaker(nil),
+	}, opts...)
+	return NewClient("secret-key", opts...)
+}
+
+func TestGetRetries(t *testing.T) {
+	ok := fakeResponse{status: http.StatusOK, body: `[]`}
+	tests := []struct {
+		name         string
+		responses    []fakeResponse
+		wantRequests int
+		wantStatus   int
+	}{
+		{
+			name:         "server errors are retried",
+			responses:    []fakeResponse{{status: 500}, {status: 502}, {status: 503}, ok},
+			wantRequests: 4,
+		},
+		{
+			name:         "rate limited requests are retried",
+			responses:    []fakeResponse{{status: 429}, ok},
+			wantRequests: 2,
+		},
+		{
+			name:         "network errors are retried",
+			responses:    []fakeResponse{{status: 0}, ok},
+			wantRequests: 2,
+		},
+		{
+			name:         "attempts are capped",
+			responses:    []fakeResponse{{status: 503}},
+			wantRequests: fastRetries.MaxAttempts,
+			wantStatus:   503,
+		},
+		{
+			name:         "bad request is not retried",
+			responses:    []fakeResponse{{status: 400}, ok},
+			wantRequests: 1,
+			wantStatus:   400,
+		},
+		{
+			name:         "unauthorized is not retried",
+			responses:    []fakeResponse{{status: 401}, ok},
+			wantRequests: 1,
+			wantStatus:   401,
+		},
+		{
+			name:         "not found is not retried",
+			responses:    []fakeResponse{{status: 404}, ok},
+			wantRequests: 1,
+			wantStatus:   404,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api, server := newFakeAPI(t, tt.responses...)
+
+			_, err := newTestClient(server).GetLastResults(context.Background(), "Lotto")
+			if tt.wantStatus == 0 && err != nil {
+				t.Fatalf("GetLastResults() error = %v", err)
+			}
+			if tt.wantStatus != 0 {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantStatus {
+					t.Fatalf("GetLastResults() error = %v, want an APIError with status %d", err, tt.wantStatus)
+				}
+			}
+			if got := api.requestCount(); got != tt.wantRequests {
+				t.Errorf("made %d requests, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestGetHonoursRetryAfter(t *testing.T) {
+	api, server := newFakeAPI(t,
+		fakeResponse{status: http.StatusTooManyRequests, retryAfter: "1"},
+		fakeResponse{status: http.StatusOK, body: `[]`},
+	)
+
+	if _, err := newTestClient(server).GetLastResults(context.Background(), "Lotto"); err != nil {
+		t.Fatalf("GetLastResults() error = %v", err)
+	}
+	if got := api.requestCount(); got != 2 {
+		t.Fatalf("made %d requests, want 2", got)
+	}
+	// the backoff alone would retry after a few milliseconds
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	if wait := api.times[1].Sub(api.times[0]); wait < time.Second {
+		t.Errorf("retried after %v, want the 1s the API asked for", wait)
+	}
+}
+
+func TestGetGivesUpOnLongRetryAfter(t *testing.T) {
+	api, server := newFakeAPI(t,
+		fakeResponse{status: http.StatusTooManyRequests, retryAfter: "3600"},
+		fakeResponse{status: http.StatusOK, body: `[]`},
+	)
+
+	_, err := newTestClient(server).GetLastResults(context.Background(), "Lotto")
+	if !IsRateLimited(err) {
+		t.Fatalf("GetLastResults() error = %v, want the rate limit error", err)
+	}
+	if got := api.requestCount(); got != 1 {
+		t.Errorf("made %d requests, want 1", got)
+	}
+}
+
+func TestGetStopsRetryingWhenCancelled(t *testing.T) {
+	api, server := newFakeAPI(t, fakeResponse{status: http.StatusServiceUnavailable, retryAfter: "1"})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := newTestClient(server).GetLastResults(ctx, "Lotto"); err == nil {
+		t.Fatal("GetLastResults() succeeded, want an error")
+	}
+	if elapsed := time.Since(start); elapsed > 900*time.Millisecond {
+		t.Errorf("returned after %v, want it to stop waiting when the context is done", elapsed)
+	}
+	if got := api.requestCount(); got != 1 {
+		t.Errorf("made %d requests, want 1", got)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{name: "missing", header: ""},
+		{name: "seconds", header: "2", wantMin: 2 * time.Second, wantMax: 2 * time.Second},
+		{name: "zero", header: "0"},
+		{name: "negative", header: "-5"},
+		{name: "invalid", header: "soon"},
+		{
+			name:    "date",
+			header:  time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat),
+			wantMin: 8 * time.Second,
+			wantMax: 10 * time.Second,
+		},
+		{name: "date in the past", header: time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.header != "" {
+				resp.Header.Set("Retry-After", tt.header)
+			}
+			if got := retryAfter(resp); got < tt.wantMin || got > tt.wantMax {
+				t.Errorf("retryAfter(%q) = %v, want %v-%v", tt.header, got, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	tests := []struct {
+		attempt int
+		full    time.Duration
+	}{
+		{attempt: 1, full: 100 * time.Millisecond},
+		{attempt: 2, full: 200 * time.Millisecond},
+		{attempt: 4, full: 800 * time.Millisecond},
+		{attempt: 5, full: time.Second},
+		{attempt: 9, full: time.Second},
+	}
+	for _, tt := range tests {
+		for range 20 {
+			if got := policy.delay(tt.attempt); got < tt.full/2 || got > tt.full {
+				t.Fatalf("delay(%d) = %v, want %v-%v", tt.attempt, got, tt.full/2, tt.full)
+			}
+		}
+	}
+}
@@ -21,6 +21,25 @@ type Draw struct {
 	IsNewEuroJackpotDraw bool      `json:"isNewEuroJackpotDraw"`
 }
 
+// DrawsPage is one page of historical draws
+type DrawsPage struct {
+	TotalRows int    `json:"totalRows"`
+	Items     []Draw `json:"items"`
+}
+
+// DrawPrizes is the prize breakdown of a draw, Prizes are keyed by the tier number, "1" being the top prize
+type DrawPrizes struct {
+	DrawSystemID uint             `json:"drawSystemId"`
+	GameType     string           `json:"gameType"`
+	Prizes       map[string]Prize `json:"prizes"`
+}
+
+type Prize struct {
+	// Winners is called prizes by the API, it is the number of winning tickets
+	Winners    int     `json:"prizes"`
+	PrizeValue float64 `json:"prizeValue"`
+}
+
 type Result struct {
 	DrawSystemID   uint      `json:"drawSystemId"`
 	DrawDate       time.Time `json:"drawDate"`